@@ -0,0 +1,35 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package panel
+
+func SilenceColumns(columns ...SilenceColumnDefinition) SilenceTableOption {
+	return func(builder *SilenceTableBuilder) error {
+		builder.Columns = columns
+		return nil
+	}
+}
+
+func AddSilenceColumn(column SilenceColumnDefinition) SilenceTableOption {
+	return func(builder *SilenceTableBuilder) error {
+		builder.Columns = append(builder.Columns, column)
+		return nil
+	}
+}
+
+func SilenceAllowedActions(actions ...SilenceTableAction) SilenceTableOption {
+	return func(builder *SilenceTableBuilder) error {
+		builder.AllowedActions = actions
+		return nil
+	}
+}
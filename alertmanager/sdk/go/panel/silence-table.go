@@ -0,0 +1,93 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package panel
+
+import (
+	"github.com/perses/perses/go-sdk/panel"
+)
+
+const SilenceTablePluginKind = "SilenceTable"
+
+type SilenceFieldName string
+
+const (
+	SilenceStatusField    SilenceFieldName = "status"
+	SilenceMatchersField  SilenceFieldName = "matchers"
+	SilenceCreatedByField SilenceFieldName = "createdBy"
+	SilenceStartsAtField  SilenceFieldName = "startsAt"
+	SilenceEndsAtField    SilenceFieldName = "endsAt"
+	SilenceDurationField  SilenceFieldName = "duration"
+	SilenceCommentField   SilenceFieldName = "comment"
+	SilenceUpdatedAtField SilenceFieldName = "updatedAt"
+)
+
+type SilenceColumnSortMode string
+
+const (
+	SilenceAlphabeticalSort SilenceColumnSortMode = "alphabetical"
+	SilenceDateSort         SilenceColumnSortMode = "date"
+	SilenceStatusSort       SilenceColumnSortMode = "status"
+)
+
+type SilenceTableAction string
+
+const (
+	ExpireAction SilenceTableAction = "expire"
+)
+
+type SilenceColumnDefinition struct {
+	Name          SilenceFieldName      `json:"name" yaml:"name"`
+	Header        string                `json:"header,omitempty" yaml:"header,omitempty"`
+	EnableSorting *bool                 `json:"enableSorting,omitempty" yaml:"enableSorting,omitempty"`
+	Sort          SortDirection         `json:"sort,omitempty" yaml:"sort,omitempty"`
+	SortMode      SilenceColumnSortMode `json:"sortMode,omitempty" yaml:"sortMode,omitempty"`
+}
+
+type SilenceTablePluginSpec struct {
+	Columns        []SilenceColumnDefinition `json:"columns,omitempty" yaml:"columns,omitempty"`
+	AllowedActions []SilenceTableAction      `json:"allowedActions,omitempty" yaml:"allowedActions,omitempty"`
+}
+
+type SilenceTableOption func(plugin *SilenceTableBuilder) error
+
+func createSilenceTable(options ...SilenceTableOption) (SilenceTableBuilder, error) {
+	builder := &SilenceTableBuilder{
+		SilenceTablePluginSpec: SilenceTablePluginSpec{},
+	}
+
+	for _, opt := range options {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+
+	return *builder, nil
+}
+
+type SilenceTableBuilder struct {
+	SilenceTablePluginSpec `json:",inline" yaml:",inline"`
+}
+
+func SilenceTable(options ...SilenceTableOption) panel.Option {
+	return func(builder *panel.Builder) error {
+		plugin, err := createSilenceTable(options...)
+		if err != nil {
+			return err
+		}
+
+		builder.Spec.Plugin.Kind = SilenceTablePluginKind
+		builder.Spec.Plugin.Spec = plugin.SilenceTablePluginSpec
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package panel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/panel"
+)
+
+func TestSilenceTable(t *testing.T) {
+	enabled := true
+	testSuites := []struct {
+		title    string
+		options  []SilenceTableOption
+		expected string
+	}{
+		{
+			title:    "no option",
+			expected: `{}`,
+		},
+		{
+			title: "columns and actions",
+			options: []SilenceTableOption{
+				SilenceColumns(SilenceColumnDefinition{Name: SilenceStatusField}),
+				AddSilenceColumn(SilenceColumnDefinition{Name: SilenceEndsAtField, Header: "Ends", EnableSorting: &enabled, Sort: DescSort, SortMode: SilenceDateSort}),
+				SilenceAllowedActions(ExpireAction),
+			},
+			expected: `{"columns":[{"name":"status"},{"name":"endsAt","header":"Ends","enableSorting":true,"sort":"desc","sortMode":"date"}],"allowedActions":["expire"]}`,
+		},
+		{
+			title: "columns replaced",
+			options: []SilenceTableOption{
+				AddSilenceColumn(SilenceColumnDefinition{Name: SilenceCommentField}),
+				SilenceColumns(SilenceColumnDefinition{Name: SilenceMatchersField}, SilenceColumnDefinition{Name: SilenceCreatedByField}),
+			},
+			expected: `{"columns":[{"name":"matchers"},{"name":"createdBy"}]}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder := &panel.Builder{}
+			if err := SilenceTable(test.options...)(builder); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if builder.Spec.Plugin.Kind != SilenceTablePluginKind {
+				t.Fatalf("unexpected kind: %s", builder.Spec.Plugin.Kind)
+			}
+			raw, err := json.Marshal(builder.Spec.Plugin.Spec)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != test.expected {
+				t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, test.expected)
+			}
+		})
+	}
+}
+
+func TestSilenceTableUnmarshal(t *testing.T) {
+	data := `{"columns":[{"name":"duration","sortMode":"alphabetical"}],"allowedActions":["expire"]}`
+	var spec SilenceTablePluginSpec
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spec.Columns) != 1 || spec.Columns[0].Name != SilenceDurationField || spec.Columns[0].SortMode != SilenceAlphabeticalSort {
+		t.Errorf("unexpected columns: %+v", spec.Columns)
+	}
+	if len(spec.AllowedActions) != 1 || spec.AllowedActions[0] != ExpireAction {
+		t.Errorf("unexpected actions: %+v", spec.AllowedActions)
+	}
+}
@@ -2,11 +2,13 @@ module github.com/perses/plugins/barchart
 
 go 1.26.5
 
-require github.com/perses/perses v0.54.0
+require (
+	github.com/perses/perses v0.54.0
+	github.com/perses/spec v0.3.0-beta.2
+)
 
 require (
 	github.com/kr/pretty v0.3.1 // indirect
-	github.com/perses/spec v0.3.0-beta.2 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -2,7 +2,10 @@ module github.com/perses/plugins/datasourcevariable
 
 go 1.26.5
 
-require github.com/perses/perses v0.54.0
+require (
+	github.com/perses/perses v0.54.0
+	github.com/perses/spec v0.3.0-beta.2
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -15,7 +18,6 @@ require (
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/mwitkow/go-conntrack v0.0.0-20190716064945-2f068394615f // indirect
 	github.com/perses/common v0.31.2 // indirect
-	github.com/perses/spec v0.3.0-beta.2 // indirect
 	github.com/prometheus/client_golang v1.23.2 // indirect
 	github.com/prometheus/client_model v0.6.2 // indirect
 	github.com/prometheus/common v0.70.0 // indirect
@@ -0,0 +1,25 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasourcevariable
+
+import (
+	datasourcevariable "github.com/perses/plugins/datasourcevariable/sdk/go"
+	"github.com/perses/spec/go/plugin"
+)
+
+// Register records the spec type of every plugin provided by the Go SDK of the module.
+// register is usually the Register method of a registry.Registry from github.com/perses/plugins/sdk/go/registry.
+func Register(register func(kind string, pluginType string, spec any) error) error {
+	return register(datasourcevariable.PluginKind, string(plugin.KindVariable), datasourcevariable.PluginSpec{})
+}
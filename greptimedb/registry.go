@@ -0,0 +1,36 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package greptimedb
+
+import (
+	"errors"
+
+	"github.com/perses/plugins/greptimedb/sdk/go/datasource"
+	"github.com/perses/plugins/greptimedb/sdk/go/query/log"
+	timeseries "github.com/perses/plugins/greptimedb/sdk/go/query/time-series"
+	"github.com/perses/plugins/greptimedb/sdk/go/query/trace"
+	datasourceSpec "github.com/perses/spec/go/datasource"
+	"github.com/perses/spec/go/plugin"
+)
+
+// Register records the spec type of every plugin provided by the Go SDK of the module.
+// register is usually the Register method of a registry.Registry from github.com/perses/plugins/sdk/go/registry.
+func Register(register func(kind string, pluginType string, spec any) error) error {
+	return errors.Join(
+		register(datasource.PluginKind, string(plugin.KindDatasource), datasourceSpec.HTTPDatasourceSpec{}),
+		register(log.PluginKind, string(plugin.KindLogQuery), log.PluginSpec{}),
+		register(timeseries.PluginKind, string(plugin.KindTimeSeriesQuery), timeseries.PluginSpec{}),
+		register(trace.PluginKind, string(plugin.KindTraceQuery), trace.PluginSpec{}),
+	)
+}
@@ -13,7 +13,15 @@
 
 package query
 
-import "github.com/perses/plugins/jaeger/sdk/go/datasource"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/perses/plugins/jaeger/sdk/go/datasource"
+)
 
 func Datasource(datasourceName string) Option {
 	return func(builder *Builder) error {
@@ -43,13 +51,55 @@ func Operation(operation string) Option {
 	}
 }
 
+// SearchKind searches the spans of the given kind.
+func SearchKind(kind Kind) Option {
+	return func(builder *Builder) error {
+		switch kind {
+		case KindServer, KindClient, KindProducer, KindConsumer, KindInternal:
+		default:
+			return fmt.Errorf("invalid span kind %q, the supported values are %q, %q, %q, %q and %q", kind, KindServer, KindClient, KindProducer, KindConsumer, KindInternal)
+		}
+		builder.SpanKind = kind
+		return nil
+	}
+}
+
+// Deprecated: use SearchKind, which only accepts the kinds supported by Jaeger.
 func SpanKind(spanKind string) Option {
 	return func(builder *Builder) error {
-		builder.SpanKind = spanKind
+		builder.SpanKind = Kind(spanKind)
+		return nil
+	}
+}
+
+// Tag searches the spans having the tag with the given value. It can be used several times to search on more tags.
+func Tag(key string, value string) Option {
+	return func(builder *Builder) error {
+		if key == "" {
+			return fmt.Errorf("the tag key cannot be empty")
+		}
+		if builder.tags == nil {
+			builder.tags = make(map[string]string)
+		}
+		builder.tags[key] = value
+		return nil
+	}
+}
+
+// TagMap searches the spans having all the given tags, as Tag does for each of them.
+func TagMap(tags map[string]string) Option {
+	return func(builder *Builder) error {
+		for _, key := range slices.Sorted(maps.Keys(tags)) {
+			if err := Tag(key, tags[key])(builder); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 }
 
+// Deprecated: use Tag for each tag, which builds the JSON object expected by the Jaeger query plugin.
+// The given tags are used as is and cannot be combined with Tag.
 func Tags(tags string) Option {
 	return func(builder *Builder) error {
 		builder.Tags = tags
@@ -57,15 +107,43 @@ func Tags(tags string) Option {
 	}
 }
 
+// SearchMinDuration searches the spans lasting at least the given duration.
+func SearchMinDuration(duration time.Duration) Option {
+	return func(builder *Builder) error {
+		if duration <= 0 {
+			return fmt.Errorf("the min duration must be positive, got %s", duration)
+		}
+		builder.minDuration = duration
+		builder.MinDuration = formatDuration(duration)
+		return nil
+	}
+}
+
+// Deprecated: use SearchMinDuration, which formats the duration as expected by Jaeger.
 func MinDuration(duration string) Option {
 	return func(builder *Builder) error {
+		builder.minDuration, _ = time.ParseDuration(duration)
 		builder.MinDuration = duration
 		return nil
 	}
 }
 
+// SearchMaxDuration searches the spans lasting at most the given duration.
+func SearchMaxDuration(duration time.Duration) Option {
+	return func(builder *Builder) error {
+		if duration <= 0 {
+			return fmt.Errorf("the max duration must be positive, got %s", duration)
+		}
+		builder.maxDuration = duration
+		builder.MaxDuration = formatDuration(duration)
+		return nil
+	}
+}
+
+// Deprecated: use SearchMaxDuration, which formats the duration as expected by Jaeger.
 func MaxDuration(duration string) Option {
 	return func(builder *Builder) error {
+		builder.maxDuration, _ = time.ParseDuration(duration)
 		builder.MaxDuration = duration
 		return nil
 	}
@@ -77,3 +155,24 @@ func Limit(limit int) Option {
 		return nil
 	}
 }
+
+var durationUnits = []struct {
+	unit     string
+	duration time.Duration
+}{
+	{unit: "h", duration: time.Hour},
+	{unit: "m", duration: time.Minute},
+	{unit: "s", duration: time.Second},
+	{unit: "ms", duration: time.Millisecond},
+	{unit: "us", duration: time.Microsecond},
+}
+
+// formatDuration renders the duration with the largest unit dividing it, e.g. `1500ms`, as parsed by Jaeger.
+func formatDuration(d time.Duration) string {
+	for _, u := range durationUnits {
+		if d%u.duration == 0 {
+			return strconv.FormatInt(int64(d/u.duration), 10) + u.unit
+		}
+	}
+	return strconv.FormatInt(int64(d), 10) + "ns"
+}
@@ -14,6 +14,12 @@
 package query
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/go-sdk/query"
 	"github.com/perses/spec/go/plugin"
@@ -21,12 +27,23 @@ import (
 
 const PluginKind = "JaegerTraceQuery"
 
+// Kind is the kind of the spans searched by the query, see SearchKind.
+type Kind string
+
+const (
+	KindServer   Kind = "server"
+	KindClient   Kind = "client"
+	KindProducer Kind = "producer"
+	KindConsumer Kind = "consumer"
+	KindInternal Kind = "internal"
+)
+
 type PluginSpec struct {
 	Datasource  *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
 	TraceID     string               `json:"traceId,omitempty" yaml:"traceId,omitempty"`
 	Service     string               `json:"service,omitempty" yaml:"service,omitempty"`
 	Operation   string               `json:"operation,omitempty" yaml:"operation,omitempty"`
-	SpanKind    string               `json:"spanKind,omitempty" yaml:"spanKind,omitempty"`
+	SpanKind    Kind                 `json:"spanKind,omitempty" yaml:"spanKind,omitempty"`
 	Tags        string               `json:"tags,omitempty" yaml:"tags,omitempty"`
 	MinDuration string               `json:"minDuration,omitempty" yaml:"minDuration,omitempty"`
 	MaxDuration string               `json:"maxDuration,omitempty" yaml:"maxDuration,omitempty"`
@@ -46,11 +63,62 @@ func create(options ...Option) (Builder, error) {
 		}
 	}
 
+	if len(builder.tags) > 0 {
+		if builder.Tags != "" {
+			return *builder, fmt.Errorf("the tags given with Tags cannot be combined with Tag")
+		}
+		// the Jaeger query plugin expects the tags as a JSON object
+		data, err := json.Marshal(builder.tags)
+		if err != nil {
+			return *builder, err
+		}
+		builder.Tags = string(data)
+	}
+
+	if err := builder.validate(); err != nil {
+		return *builder, err
+	}
+
 	return *builder, nil
 }
 
 type Builder struct {
-	PluginSpec `json:",inline" yaml:",inline"`
+	PluginSpec  `json:",inline" yaml:",inline"`
+	tags        map[string]string
+	minDuration time.Duration
+	maxDuration time.Duration
+}
+
+// validate checks that the query either gets a trace by its ID or searches the traces of a service.
+func (b *Builder) validate() error {
+	if b.TraceID != "" {
+		var params []string
+		for name, set := range map[string]bool{
+			"service":     b.Service != "",
+			"operation":   b.Operation != "",
+			"spanKind":    b.SpanKind != "",
+			"tags":        b.Tags != "",
+			"minDuration": b.MinDuration != "",
+			"maxDuration": b.MaxDuration != "",
+			"limit":       b.Limit != nil,
+		} {
+			if set {
+				params = append(params, name)
+			}
+		}
+		if len(params) > 0 {
+			sort.Strings(params)
+			return fmt.Errorf("the trace ID cannot be combined with the search parameters %s", strings.Join(params, ", "))
+		}
+		return nil
+	}
+	if b.Service == "" {
+		return fmt.Errorf("either a trace ID or a service to search is required")
+	}
+	if b.minDuration > 0 && b.maxDuration > 0 && b.minDuration > b.maxDuration {
+		return fmt.Errorf("the min duration %s is greater than the max duration %s", b.MinDuration, b.MaxDuration)
+	}
+	return nil
 }
 
 func Trace(options ...Option) query.Option {
@@ -0,0 +1,164 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title:    "trace ID",
+			options:  []Option{Datasource("jaeger"), TraceID("4bf92f3577b34da6a3ce929d0e0e4736")},
+			expected: `{"datasource":{"kind":"JaegerDatasource","name":"jaeger"},"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"}`,
+		},
+		{
+			title: "search",
+			options: []Option{
+				Service("checkout"),
+				Operation("GET /cart"),
+				SearchKind(KindServer),
+				Tag("http.status_code", "500"),
+				Tag("error", "true"),
+				SearchMinDuration(1500 * time.Millisecond),
+				SearchMaxDuration(2 * time.Minute),
+				Limit(20),
+			},
+			expected: `{"service":"checkout","operation":"GET /cart","spanKind":"server","tags":"{\"error\":\"true\",\"http.status_code\":\"500\"}","minDuration":"1500ms","maxDuration":"2m","limit":20}`,
+		},
+		{
+			title:    "tag map",
+			options:  []Option{Service("checkout"), Tag("error", "true"), TagMap(map[string]string{"http.method": "GET", "http.status_code": "500"})},
+			expected: `{"service":"checkout","tags":"{\"error\":\"true\",\"http.method\":\"GET\",\"http.status_code\":\"500\"}"}`,
+		},
+		{
+			title:    "deprecated options",
+			options:  []Option{Service("checkout"), SpanKind("client"), Tags(`{"error":"true"}`), MinDuration("100ms"), MaxDuration("1s")},
+			expected: `{"service":"checkout","spanKind":"client","tags":"{\"error\":\"true\"}","minDuration":"100ms","maxDuration":"1s"}`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("unexpected spec:\n got: %s\nwant: %s", data, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "trace ID with search parameters",
+			options: []Option{TraceID("4bf92f3577b34da6"), Service("checkout"), SearchKind(KindClient), Limit(10)},
+			err:     "the trace ID cannot be combined with the search parameters limit, service, spanKind",
+		},
+		{
+			title: "neither trace ID nor service",
+			err:   "either a trace ID or a service to search is required",
+		},
+		{
+			title:   "invalid span kind",
+			options: []Option{Service("checkout"), SearchKind("server ")},
+			err:     `invalid span kind "server "`,
+		},
+		{
+			title:   "empty tag key",
+			options: []Option{Service("checkout"), Tag("", "true")},
+			err:     "the tag key cannot be empty",
+		},
+		{
+			title:   "empty tag key in tag map",
+			options: []Option{Service("checkout"), TagMap(map[string]string{"error": "true", "": "500"})},
+			err:     "the tag key cannot be empty",
+		},
+		{
+			title:   "tags combined with tag",
+			options: []Option{Service("checkout"), Tags(`{"error":"true"}`), Tag("http.status_code", "500")},
+			err:     "the tags given with Tags cannot be combined with Tag",
+		},
+		{
+			title:   "negative min duration",
+			options: []Option{Service("checkout"), SearchMinDuration(-time.Second)},
+			err:     "the min duration must be positive, got -1s",
+		},
+		{
+			title:   "zero max duration",
+			options: []Option{Service("checkout"), SearchMaxDuration(0)},
+			err:     "the max duration must be positive, got 0s",
+		},
+		{
+			title:   "min duration greater than max duration",
+			options: []Option{Service("checkout"), SearchMinDuration(2 * time.Second), SearchMaxDuration(time.Second)},
+			err:     "the min duration 2s is greater than the max duration 1s",
+		},
+		{
+			title:   "deprecated min duration greater than max duration",
+			options: []Option{Service("checkout"), MinDuration("1.5s"), MaxDuration("500ms")},
+			err:     "the min duration 1.5s is greater than the max duration 500ms",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatalf("expected the error %q, got nil", test.err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("unexpected error: got %q, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	testSuite := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 2 * time.Hour, expected: "2h"},
+		{duration: 90 * time.Minute, expected: "90m"},
+		{duration: 90 * time.Second, expected: "90s"},
+		{duration: 1500 * time.Millisecond, expected: "1500ms"},
+		{duration: 250 * time.Microsecond, expected: "250us"},
+		{duration: 1001 * time.Nanosecond, expected: "1001ns"},
+	}
+	for _, test := range testSuite {
+		t.Run(test.duration.String(), func(t *testing.T) {
+			if actual := formatDuration(test.duration); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
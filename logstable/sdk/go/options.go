@@ -33,3 +33,24 @@ func ShowTime(showTime bool) Option {
 		return nil
 	}
 }
+
+func WithSelection(selection Selection) Option {
+	return func(builder *Builder) error {
+		builder.Selection = &selection
+		return nil
+	}
+}
+
+func WithActions(actions Actions) Option {
+	return func(builder *Builder) error {
+		builder.Actions = NewActions(actions)
+		return nil
+	}
+}
+
+func AddAction(action ItemAction) Option {
+	return func(builder *Builder) error {
+		builder.Actions = AppendAction(builder.Actions, action)
+		return nil
+	}
+}
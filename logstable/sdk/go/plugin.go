@@ -13,16 +13,16 @@
 
 package logstable
 
-import (
-	"github.com/perses/perses/go-sdk/panel"
-)
+import "github.com/perses/perses/go-sdk/panel"
 
 const PluginKind = "LogsTable"
 
 type PluginSpec struct {
-	AllowWrap     *bool `json:"allowWrap,omitempty" yaml:"allowWrap,omitempty"`
-	EnableDetails *bool `json:"enableDetails,omitempty" yaml:"enableDetails,omitempty"`
-	ShowTime      *bool `json:"showTime,omitempty" yaml:"showTime,omitempty"`
+	AllowWrap     *bool      `json:"allowWrap,omitempty" yaml:"allowWrap,omitempty"`
+	EnableDetails *bool      `json:"enableDetails,omitempty" yaml:"enableDetails,omitempty"`
+	ShowTime      *bool      `json:"showTime,omitempty" yaml:"showTime,omitempty"`
+	Selection     *Selection `json:"selection,omitempty" yaml:"selection,omitempty"`
+	Actions       *Actions   `json:"actions,omitempty" yaml:"actions,omitempty"`
 }
 
 type Option func(plugin *Builder) error
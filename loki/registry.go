@@ -0,0 +1,40 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"errors"
+
+	"github.com/perses/plugins/loki/sdk/go/datasource"
+	"github.com/perses/plugins/loki/sdk/go/query/log"
+	timeseries "github.com/perses/plugins/loki/sdk/go/query/time-series"
+	labelnames "github.com/perses/plugins/loki/sdk/go/variable/label-names"
+	labelvalues "github.com/perses/plugins/loki/sdk/go/variable/label-values"
+	"github.com/perses/plugins/loki/sdk/go/variable/logql"
+	datasourceSpec "github.com/perses/spec/go/datasource"
+	"github.com/perses/spec/go/plugin"
+)
+
+// Register records the spec type of every plugin provided by the Go SDK of the module.
+// register is usually the Register method of a registry.Registry from github.com/perses/plugins/sdk/go/registry.
+func Register(register func(kind string, pluginType string, spec any) error) error {
+	return errors.Join(
+		register(datasource.PluginKind, string(plugin.KindDatasource), datasourceSpec.HTTPDatasourceSpec{}),
+		register(log.PluginKind, string(plugin.KindLogQuery), log.PluginSpec{}),
+		register(timeseries.PluginKind, string(plugin.KindTimeSeriesQuery), timeseries.PluginSpec{}),
+		register(labelnames.PluginKind, string(plugin.KindVariable), labelnames.PluginSpec{}),
+		register(labelvalues.PluginKind, string(plugin.KindVariable), labelvalues.PluginSpec{}),
+		register(logql.PluginKind, string(plugin.KindVariable), logql.PluginSpec{}),
+	)
+}
@@ -15,6 +15,7 @@ package log
 
 import (
 	lokiDatasource "github.com/perses/plugins/loki/sdk/go/datasource"
+	"github.com/perses/plugins/loki/sdk/go/query/logql"
 )
 
 func Query(expr string) Option {
@@ -24,6 +25,11 @@ func Query(expr string) Option {
 	}
 }
 
+// Expression defines the query from a log query built with the logql package.
+func Expression(expr logql.LogQuery) Option {
+	return Query(expr.String())
+}
+
 func Datasource(datasourceName string) Option {
 	return func(builder *Builder) error {
 		builder.Datasource = lokiDatasource.Selector(datasourceName)
@@ -0,0 +1,115 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logql builds LogQL expressions from typed Go values.
+//
+// A log query starts with a stream selector, followed by the stages of its pipeline in the order they are applied:
+//
+//	logql.Stream(logql.Eq("app", "api")).Contains("error").JSON().Where(logql.Compare("status", logql.GreaterOrEqual, 500))
+//
+// renders `{app="api"} |= "error" | json | status>=500`. The log queries feed the log query plugin, and the metric
+// queries built on top of them (Rate, CountOverTime, Sum...) feed the time series query plugin.
+package logql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a LogQL expression.
+type Expr interface {
+	// String returns the expression in the LogQL format.
+	String() string
+}
+
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher filters the streams, or the log lines in a label filter, on the value of a label.
+type Matcher struct {
+	Label string
+	Type  MatchType
+	Value string
+}
+
+// Eq matches the label equal to the value, e.g. `app="api"`.
+func Eq(label string, value string) Matcher {
+	return Matcher{Label: label, Type: MatchEqual, Value: value}
+}
+
+// Neq matches the label not equal to the value, e.g. `app!="api"`.
+func Neq(label string, value string) Matcher {
+	return Matcher{Label: label, Type: MatchNotEqual, Value: value}
+}
+
+// Re matches the label matching the regular expression, e.g. `pod=~"$pod"`.
+func Re(label string, regexp string) Matcher {
+	return Matcher{Label: label, Type: MatchRegexp, Value: regexp}
+}
+
+// NotRe matches the label not matching the regular expression, e.g. `level!~"debug|trace"`.
+func NotRe(label string, regexp string) Matcher {
+	return Matcher{Label: label, Type: MatchNotRegexp, Value: regexp}
+}
+
+func (m Matcher) String() string {
+	return m.Label + string(m.Type) + strconv.Quote(m.Value)
+}
+
+func (m Matcher) labelFilter() {}
+
+var durationUnits = []struct {
+	unit     string
+	duration time.Duration
+}{
+	{unit: "y", duration: 365 * 24 * time.Hour},
+	{unit: "w", duration: 7 * 24 * time.Hour},
+	{unit: "d", duration: 24 * time.Hour},
+	{unit: "h", duration: time.Hour},
+	{unit: "m", duration: time.Minute},
+	{unit: "s", duration: time.Second},
+	{unit: "ms", duration: time.Millisecond},
+}
+
+// formatDuration renders a duration like Loki does for the ranges, e.g. `1h30m`. The precision is the millisecond.
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	rest := d
+	for _, u := range durationUnits {
+		if rest >= u.duration {
+			b.WriteString(strconv.FormatInt(int64(rest/u.duration), 10))
+			b.WriteString(u.unit)
+			rest %= u.duration
+		}
+	}
+	if rest == d {
+		// nothing has been written, the duration is lower than the precision
+		return "0s"
+	}
+	return b.String()
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,143 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExprString(t *testing.T) {
+	api := Stream(Eq("app", "api"), Re("env", "$env"))
+	testSuites := []struct {
+		title    string
+		expr     Expr
+		expected string
+	}{
+		{
+			title:    "stream selector",
+			expr:     api,
+			expected: `{app="api", env=~"$env"}`,
+		},
+		{
+			title:    "escaped label value",
+			expr:     Stream(Eq("path", `C:\logs\"app"`)),
+			expected: `{path="C:\\logs\\\"app\""}`,
+		},
+		{
+			title:    "line filters",
+			expr:     api.Contains("error").NotContains("healthz").Match(`time(out)?`).NotMatch(`\d{3}`),
+			expected: `{app="api", env=~"$env"} |= "error" != "healthz" |~ "time(out)?" !~ "\\d{3}"`,
+		},
+		{
+			title:    "json parser and label filters",
+			expr:     api.Contains("error").JSON().Where(Compare("status", GreaterOrEqual, 500), Neq("method", "GET")),
+			expected: `{app="api", env=~"$env"} |= "error" | json | status>=500 | method!="GET"`,
+		},
+		{
+			title:    "parsers with extractions",
+			expr:     api.JSON(Extract("status", "response.status")).Logfmt(Extract("lvl", "level")),
+			expected: `{app="api", env=~"$env"} | json status="response.status" | logfmt lvl="level"`,
+		},
+		{
+			title:    "pattern and formatting",
+			expr:     api.Pattern(`<ip> - - <_> "<method> <uri> <_>"`).LineFormat("{{.method}} {{.uri}}").LabelFormat("level", "{{ToUpper .level}}"),
+			expected: `{app="api", env=~"$env"} | pattern "<ip> - - <_> \"<method> <uri> <_>\"" | line_format "{{.method}} {{.uri}}" | label_format level="{{ToUpper .level}}"`,
+		},
+		{
+			title:    "duration and bytes filters",
+			expr:     api.Logfmt().Where(CompareDuration("latency", GreaterThan, 1500*time.Millisecond), CompareBytes("size", LessOrEqual, 1024)),
+			expected: `{app="api", env=~"$env"} | logfmt | latency>1.5s | size<=1024B`,
+		},
+		{
+			title:    "drop and keep",
+			expr:     api.Regexp(`(?P<method>\w+) `).Drop("pod", "node").Keep("method"),
+			expected: `{app="api", env=~"$env"} | regexp "(?P<method>\\w+) " | drop pod, node | keep method`,
+		},
+		{
+			title:    "rate",
+			expr:     Rate(api.Range(5 * time.Minute)),
+			expected: `rate({app="api", env=~"$env"}[5m])`,
+		},
+		{
+			title:    "count over time with a pipeline",
+			expr:     CountOverTime(api.Contains("error").RangeVar("__interval")),
+			expected: `count_over_time({app="api", env=~"$env"} |= "error" [$__interval])`,
+		},
+		{
+			title:    "sum over time",
+			expr:     SumOverTime(api.Logfmt().UnwrapBytes("size").Range(time.Hour + 30*time.Minute)),
+			expected: `sum_over_time({app="api", env=~"$env"} | logfmt | unwrap bytes(size) [1h30m])`,
+		},
+		{
+			title:    "quantile over time by path",
+			expr:     QuantileOverTime(0.99, api.JSON().UnwrapDuration("latency").Range(time.Minute)).By("path"),
+			expected: `quantile_over_time(0.99, {app="api", env=~"$env"} | json | unwrap duration(latency) [1m]) by (path)`,
+		},
+		{
+			title:    "avg over time without pod",
+			expr:     AvgOverTime(api.Logfmt().Unwrap("cpu").Range(time.Minute)).Without("pod"),
+			expected: `avg_over_time({app="api", env=~"$env"} | logfmt | unwrap cpu [1m]) without (pod)`,
+		},
+		{
+			title:    "sum by",
+			expr:     Sum(Rate(Stream(Eq("env", "prod")).Range(5*time.Minute))).By("app", "level"),
+			expected: `sum by (app, level) (rate({env="prod"}[5m]))`,
+		},
+		{
+			title:    "topk without",
+			expr:     TopK(5, Sum(BytesRate(api.Range(time.Minute))).By("pod")).Without("node"),
+			expected: `topk without (node) (5, sum by (pod) (bytes_rate({app="api", env=~"$env"}[1m])))`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if actual := test.expr.String(); actual != test.expected {
+				t.Errorf("unexpected expression:\n got: %s\nwant: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestLogQueryIsImmutable(t *testing.T) {
+	base := Stream(Eq("app", "api")).Contains("error")
+	jsonQuery := base.JSON()
+	logfmtQuery := base.Logfmt()
+	if expected := `{app="api"} |= "error" | json`; jsonQuery.String() != expected {
+		t.Errorf("unexpected expression:\n got: %s\nwant: %s", jsonQuery.String(), expected)
+	}
+	if expected := `{app="api"} |= "error" | logfmt`; logfmtQuery.String() != expected {
+		t.Errorf("unexpected expression:\n got: %s\nwant: %s", logfmtQuery.String(), expected)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	testSuites := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 0, expected: "0s"},
+		{duration: 500 * time.Microsecond, expected: "0s"},
+		{duration: 1500 * time.Millisecond, expected: "1s500ms"},
+		{duration: 90 * time.Minute, expected: "1h30m"},
+		{duration: 8 * 24 * time.Hour, expected: "1w1d"},
+	}
+	for _, test := range testSuites {
+		t.Run(test.expected, func(t *testing.T) {
+			if actual := formatDuration(test.duration); actual != test.expected {
+				t.Errorf("unexpected duration: got %s, want %s", actual, test.expected)
+			}
+		})
+	}
+}
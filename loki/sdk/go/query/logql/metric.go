@@ -0,0 +1,259 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MetricExpr is a LogQL expression returning samples instead of log lines, used by the time series queries.
+type MetricExpr interface {
+	Expr
+	metricExpr()
+}
+
+// LogRange is a log query over a range of time, e.g. `{app="api"} |= "error" [5m]`, see LogQuery.Range.
+type LogRange struct {
+	query         LogQuery
+	rangeDuration string
+}
+
+func (r LogRange) String() string {
+	if len(r.query.stages) == 0 {
+		return r.query.String() + "[" + r.rangeDuration + "]"
+	}
+	return r.query.String() + " [" + r.rangeDuration + "]"
+}
+
+// UnwrappedRange is an unwrapped log query over a range of time, e.g. `{app="api"} | logfmt | unwrap latency [5m]`,
+// see UnwrappedQuery.Range.
+type UnwrappedRange struct {
+	query         UnwrappedQuery
+	rangeDuration string
+}
+
+func (r UnwrappedRange) String() string {
+	return r.query.String() + " [" + r.rangeDuration + "]"
+}
+
+// RangeAggregation aggregates each log stream over a range of time, e.g. `rate({app="api"}[5m])`.
+type RangeAggregation struct {
+	operator string
+	rng      Expr
+}
+
+// Rate computes the number of log lines per second, e.g. `rate({app="api"}[5m])`.
+func Rate(r LogRange) RangeAggregation {
+	return RangeAggregation{operator: "rate", rng: r}
+}
+
+// CountOverTime counts the log lines, e.g. `count_over_time({app="api"}[5m])`.
+func CountOverTime(r LogRange) RangeAggregation {
+	return RangeAggregation{operator: "count_over_time", rng: r}
+}
+
+// BytesRate computes the number of bytes per second of the log lines, e.g. `bytes_rate({app="api"}[5m])`.
+func BytesRate(r LogRange) RangeAggregation {
+	return RangeAggregation{operator: "bytes_rate", rng: r}
+}
+
+// BytesOverTime computes the number of bytes of the log lines, e.g. `bytes_over_time({app="api"}[5m])`.
+func BytesOverTime(r LogRange) RangeAggregation {
+	return RangeAggregation{operator: "bytes_over_time", rng: r}
+}
+
+// AbsentOverTime returns 1 when there is no log line, e.g. `absent_over_time({app="api"}[5m])`.
+func AbsentOverTime(r LogRange) RangeAggregation {
+	return RangeAggregation{operator: "absent_over_time", rng: r}
+}
+
+// SumOverTime sums the unwrapped values, e.g. `sum_over_time({app="api"} | logfmt | unwrap bytes [5m])`.
+// Loki doesn't support grouping this aggregation, use Sum on top of it instead.
+func SumOverTime(r UnwrappedRange) RangeAggregation {
+	return RangeAggregation{operator: "sum_over_time", rng: r}
+}
+
+func (a RangeAggregation) String() string {
+	return a.operator + "(" + a.rng.String() + ")"
+}
+
+func (a RangeAggregation) metricExpr() {}
+
+// UnwrappedAggregation aggregates the unwrapped values of each log stream over a range of time, optionally grouped by
+// labels, e.g. `quantile_over_time(0.99, {app="api"} | logfmt | unwrap duration(latency) [5m]) by (path)`.
+type UnwrappedAggregation struct {
+	operator string
+	param    string
+	rng      UnwrappedRange
+	without  bool
+	labels   []string
+}
+
+func unwrappedAggregation(operator string, r UnwrappedRange) UnwrappedAggregation {
+	return UnwrappedAggregation{operator: operator, rng: r}
+}
+
+func AvgOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("avg_over_time", r)
+}
+
+func MinOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("min_over_time", r)
+}
+
+func MaxOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("max_over_time", r)
+}
+
+func FirstOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("first_over_time", r)
+}
+
+func LastOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("last_over_time", r)
+}
+
+func StddevOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("stddev_over_time", r)
+}
+
+func StdvarOverTime(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("stdvar_over_time", r)
+}
+
+// RateCounter computes the per-second rate of the unwrapped values, treated as a counter.
+func RateCounter(r UnwrappedRange) UnwrappedAggregation {
+	return unwrappedAggregation("rate_counter", r)
+}
+
+// QuantileOverTime computes the φ-quantile of the unwrapped values, e.g. `quantile_over_time(0.99, ...)`.
+func QuantileOverTime(quantile float64, r UnwrappedRange) UnwrappedAggregation {
+	a := unwrappedAggregation("quantile_over_time", r)
+	a.param = formatNumber(quantile)
+	return a
+}
+
+// By keeps the given labels in the result of the aggregation, e.g. `avg_over_time(...) by (path)`.
+func (a UnwrappedAggregation) By(labels ...string) UnwrappedAggregation {
+	a.without = false
+	a.labels = labels
+	return a
+}
+
+// Without removes the given labels from the result of the aggregation, e.g. `avg_over_time(...) without (pod)`.
+func (a UnwrappedAggregation) Without(labels ...string) UnwrappedAggregation {
+	a.without = true
+	a.labels = labels
+	return a
+}
+
+func (a UnwrappedAggregation) String() string {
+	result := a.operator + "("
+	if a.param != "" {
+		result += a.param + ", "
+	}
+	result += a.rng.String() + ")"
+	switch {
+	case a.without:
+		result += " without (" + strings.Join(a.labels, ", ") + ")"
+	case len(a.labels) > 0:
+		result += " by (" + strings.Join(a.labels, ", ") + ")"
+	}
+	return result
+}
+
+func (a UnwrappedAggregation) metricExpr() {}
+
+// VectorAggregation aggregates the samples of a metric query, e.g. `sum by (app) (rate({env="prod"}[5m]))`.
+type VectorAggregation struct {
+	operator string
+	param    string
+	expr     MetricExpr
+	without  bool
+	labels   []string
+}
+
+func aggregate(operator string, param string, expr MetricExpr) VectorAggregation {
+	return VectorAggregation{operator: operator, param: param, expr: expr}
+}
+
+func Sum(expr MetricExpr) VectorAggregation {
+	return aggregate("sum", "", expr)
+}
+
+func Avg(expr MetricExpr) VectorAggregation {
+	return aggregate("avg", "", expr)
+}
+
+func Min(expr MetricExpr) VectorAggregation {
+	return aggregate("min", "", expr)
+}
+
+func Max(expr MetricExpr) VectorAggregation {
+	return aggregate("max", "", expr)
+}
+
+func Count(expr MetricExpr) VectorAggregation {
+	return aggregate("count", "", expr)
+}
+
+func Stddev(expr MetricExpr) VectorAggregation {
+	return aggregate("stddev", "", expr)
+}
+
+func Stdvar(expr MetricExpr) VectorAggregation {
+	return aggregate("stdvar", "", expr)
+}
+
+// TopK keeps the k largest series, e.g. `topk(5, rate({env="prod"}[5m]))`.
+func TopK(k int, expr MetricExpr) VectorAggregation {
+	return aggregate("topk", strconv.Itoa(k), expr)
+}
+
+// BottomK keeps the k smallest series, e.g. `bottomk(5, rate({env="prod"}[5m]))`.
+func BottomK(k int, expr MetricExpr) VectorAggregation {
+	return aggregate("bottomk", strconv.Itoa(k), expr)
+}
+
+// By keeps the given labels in the result of the aggregation, e.g. `sum by (app) (...)`.
+func (a VectorAggregation) By(labels ...string) VectorAggregation {
+	a.without = false
+	a.labels = labels
+	return a
+}
+
+// Without removes the given labels from the result of the aggregation, e.g. `sum without (pod) (...)`.
+func (a VectorAggregation) Without(labels ...string) VectorAggregation {
+	a.without = true
+	a.labels = labels
+	return a
+}
+
+func (a VectorAggregation) String() string {
+	result := a.operator
+	switch {
+	case a.without:
+		result += " without (" + strings.Join(a.labels, ", ") + ") "
+	case len(a.labels) > 0:
+		result += " by (" + strings.Join(a.labels, ", ") + ") "
+	}
+	result += "("
+	if a.param != "" {
+		result += a.param + ", "
+	}
+	return result + a.expr.String() + ")"
+}
+
+func (a VectorAggregation) metricExpr() {}
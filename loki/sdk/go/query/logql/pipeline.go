@@ -0,0 +1,237 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogQuery selects log lines: a stream selector followed by a pipeline, e.g. `{app="api"} |= "error" | json`.
+// The stages of the pipeline are applied in the order of the method calls.
+type LogQuery struct {
+	matchers []Matcher
+	stages   []string
+}
+
+// Stream selects the log streams matching all the matchers, e.g. `{app="api", env=~"$env"}`.
+// Loki rejects a stream selector without matcher, so at least one is required. Loki also requires one of them not to
+// match the empty string.
+func Stream(matcher Matcher, matchers ...Matcher) LogQuery {
+	return LogQuery{matchers: append([]Matcher{matcher}, matchers...)}
+}
+
+func (q LogQuery) with(stage string) LogQuery {
+	q.stages = append(append([]string{}, q.stages...), stage)
+	return q
+}
+
+// Contains keeps the lines containing the text, e.g. `|= "error"`.
+func (q LogQuery) Contains(text string) LogQuery {
+	return q.with("|= " + strconv.Quote(text))
+}
+
+// NotContains drops the lines containing the text, e.g. `!= "debug"`.
+func (q LogQuery) NotContains(text string) LogQuery {
+	return q.with("!= " + strconv.Quote(text))
+}
+
+// Match keeps the lines matching the regular expression, e.g. `|~ "time(out)?"`.
+func (q LogQuery) Match(regexp string) LogQuery {
+	return q.with("|~ " + strconv.Quote(regexp))
+}
+
+// NotMatch drops the lines matching the regular expression, e.g. `!~ "health(z)?"`.
+func (q LogQuery) NotMatch(regexp string) LogQuery {
+	return q.with("!~ " + strconv.Quote(regexp))
+}
+
+// Extraction is a label extracted by the json and logfmt parsers, see Extract.
+type Extraction struct {
+	label      string
+	expression string
+}
+
+// Extract extracts the label from the value at the given expression, e.g. `status="response.status"` for json.
+func Extract(label string, expression string) Extraction {
+	return Extraction{label: label, expression: expression}
+}
+
+func parserStage(name string, extractions []Extraction) string {
+	if len(extractions) == 0 {
+		return "| " + name
+	}
+	params := make([]string, len(extractions))
+	for i, e := range extractions {
+		params[i] = e.label + "=" + strconv.Quote(e.expression)
+	}
+	return "| " + name + " " + strings.Join(params, ", ")
+}
+
+// JSON extracts the labels from the JSON log lines: all the fields, or only the given extractions.
+func (q LogQuery) JSON(extractions ...Extraction) LogQuery {
+	return q.with(parserStage("json", extractions))
+}
+
+// Logfmt extracts the labels from the logfmt log lines: all the keys, or only the given extractions.
+func (q LogQuery) Logfmt(extractions ...Extraction) LogQuery {
+	return q.with(parserStage("logfmt", extractions))
+}
+
+// Pattern extracts the labels with a pattern, e.g. `| pattern "<ip> - - <_> \"<method> <uri> <_>\""`.
+func (q LogQuery) Pattern(pattern string) LogQuery {
+	return q.with("| pattern " + strconv.Quote(pattern))
+}
+
+// Regexp extracts the labels with the named groups of a regular expression, e.g. `| regexp "(?P<method>\\w+) "`.
+func (q LogQuery) Regexp(regexp string) LogQuery {
+	return q.with("| regexp " + strconv.Quote(regexp))
+}
+
+// Unpack extracts the labels packed by Promtail into the JSON log lines.
+func (q LogQuery) Unpack() LogQuery {
+	return q.with("| unpack")
+}
+
+// LabelFilter filters the log lines on the value of a label, see Where.
+// A Matcher compares the value as a string, Compare as a number.
+type LabelFilter interface {
+	String() string
+	labelFilter()
+}
+
+type Comparison string
+
+const (
+	Equal          Comparison = "=="
+	NotEqual       Comparison = "!="
+	GreaterThan    Comparison = ">"
+	GreaterOrEqual Comparison = ">="
+	LessThan       Comparison = "<"
+	LessOrEqual    Comparison = "<="
+)
+
+type comparison struct {
+	label    string
+	operator Comparison
+	value    string
+}
+
+func (c comparison) String() string {
+	return c.label + string(c.operator) + c.value
+}
+
+func (c comparison) labelFilter() {}
+
+// Compare compares the value of the label as a number, e.g. `status>=500`.
+func Compare(label string, operator Comparison, value float64) LabelFilter {
+	return comparison{label: label, operator: operator, value: formatNumber(value)}
+}
+
+// CompareDuration compares the value of the label as a duration, e.g. `duration>1.5s`.
+func CompareDuration(label string, operator Comparison, value time.Duration) LabelFilter {
+	return comparison{label: label, operator: operator, value: value.String()}
+}
+
+// CompareBytes compares the value of the label as a size in bytes, e.g. `size>=1048576B`.
+func CompareBytes(label string, operator Comparison, value uint64) LabelFilter {
+	return comparison{label: label, operator: operator, value: strconv.FormatUint(value, 10) + "B"}
+}
+
+// Where keeps the lines matching all the label filters. It usually follows a parser extracting the labels.
+func (q LogQuery) Where(filters ...LabelFilter) LogQuery {
+	for _, f := range filters {
+		q = q.with("| " + f.String())
+	}
+	return q
+}
+
+// LineFormat rewrites the log lines with a template, e.g. `| line_format "{{.method}} {{.uri}}"`.
+func (q LogQuery) LineFormat(template string) LogQuery {
+	return q.with("| line_format " + strconv.Quote(template))
+}
+
+// LabelFormat sets the label to the result of a template, e.g. `| label_format level="{{ToUpper .level}}"`.
+func (q LogQuery) LabelFormat(label string, template string) LogQuery {
+	return q.with("| label_format " + label + "=" + strconv.Quote(template))
+}
+
+// Drop removes the labels from the log lines.
+func (q LogQuery) Drop(labels ...string) LogQuery {
+	return q.with("| drop " + strings.Join(labels, ", "))
+}
+
+// Keep removes all the labels from the log lines except the given ones.
+func (q LogQuery) Keep(labels ...string) LogQuery {
+	return q.with("| keep " + strings.Join(labels, ", "))
+}
+
+// Range turns the query into a range of log lines covering the given duration, for the range aggregations.
+func (q LogQuery) Range(duration time.Duration) LogRange {
+	return LogRange{query: q, rangeDuration: formatDuration(duration)}
+}
+
+// RangeVar is like Range with the duration held by a variable, e.g. `[$__interval]`.
+// The name is given without the `$` prefix.
+func (q LogQuery) RangeVar(variable string) LogRange {
+	return LogRange{query: q, rangeDuration: "$" + variable}
+}
+
+// Unwrap uses the value of the label as sample value, for the unwrapped range aggregations like SumOverTime.
+func (q LogQuery) Unwrap(label string) UnwrappedQuery {
+	return UnwrappedQuery{query: q, unwrap: label}
+}
+
+// UnwrapDuration is like Unwrap for a label holding a duration, e.g. `| unwrap duration(latency)`.
+func (q LogQuery) UnwrapDuration(label string) UnwrappedQuery {
+	return UnwrappedQuery{query: q, unwrap: "duration(" + label + ")"}
+}
+
+// UnwrapBytes is like Unwrap for a label holding a size, e.g. `| unwrap bytes(size)`.
+func (q LogQuery) UnwrapBytes(label string) UnwrappedQuery {
+	return UnwrappedQuery{query: q, unwrap: "bytes(" + label + ")"}
+}
+
+func (q LogQuery) String() string {
+	matchers := make([]string, len(q.matchers))
+	for i, m := range q.matchers {
+		matchers[i] = m.String()
+	}
+	result := "{" + strings.Join(matchers, ", ") + "}"
+	for _, stage := range q.stages {
+		result += " " + stage
+	}
+	return result
+}
+
+// UnwrappedQuery is a log query whose samples are the values of a label, see LogQuery.Unwrap.
+type UnwrappedQuery struct {
+	query  LogQuery
+	unwrap string
+}
+
+// Range turns the query into a range of samples covering the given duration.
+func (q UnwrappedQuery) Range(duration time.Duration) UnwrappedRange {
+	return UnwrappedRange{query: q, rangeDuration: formatDuration(duration)}
+}
+
+// RangeVar is like Range with the duration held by a variable. The name is given without the `$` prefix.
+func (q UnwrappedQuery) RangeVar(variable string) UnwrappedRange {
+	return UnwrappedRange{query: q, rangeDuration: "$" + variable}
+}
+
+func (q UnwrappedQuery) String() string {
+	return q.query.String() + " | unwrap " + q.unwrap
+}
@@ -0,0 +1,171 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseStreamSelector returns the matchers of a stream selector, e.g. `{app="api", env=~"$env"}`.
+// An empty selector has no matcher.
+func ParseStreamSelector(selector string) ([]Matcher, error) {
+	p := &selectorParser{input: selector}
+	matchers, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream selector %q: %w", selector, err)
+	}
+	return matchers, nil
+}
+
+// MergeMatchers adds the matchers to the stream selector, e.g. `{job="loki"}` merged with `env=~"$env"` gives
+// `{job="loki", env=~"$env"}`. The labels already filtered by the selector are kept untouched: the matchers on them
+// are skipped, like the ones on a label appearing twice in the matchers.
+// An error is returned when the result has no matcher, Loki rejecting the empty stream selector.
+func MergeMatchers(selector string, matchers ...Matcher) (string, error) {
+	existing, err := ParseStreamSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	filtered := make(map[string]bool, len(existing)+len(matchers))
+	for _, m := range existing {
+		filtered[m.Label] = true
+	}
+	result := existing
+	for _, m := range matchers {
+		if filtered[m.Label] {
+			continue
+		}
+		filtered[m.Label] = true
+		result = append(result, m)
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("the stream selector %q needs at least one matcher", selector)
+	}
+	return Stream(result[0], result[1:]...).String(), nil
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) parse() ([]Matcher, error) {
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return nil, nil
+	}
+	if !p.consume("{") {
+		return nil, p.errorf(`expected "{"`)
+	}
+	var matchers []Matcher
+	for {
+		p.skipSpaces()
+		if p.consume("}") {
+			break
+		}
+		if len(matchers) > 0 {
+			if !p.consume(",") {
+				return nil, p.errorf(`expected "," or "}"`)
+			}
+			p.skipSpaces()
+		}
+		m, err := p.matcher()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	p.skipSpaces()
+	if p.pos != len(p.input) {
+		return nil, p.errorf("unexpected %q after the stream selector", p.input[p.pos:])
+	}
+	return matchers, nil
+}
+
+func (p *selectorParser) matcher() (Matcher, error) {
+	start := p.pos
+	for p.pos < len(p.input) && isLabelRune(p.input[p.pos], p.pos == start) {
+		p.pos++
+	}
+	if p.pos == start {
+		return Matcher{}, p.errorf("expected a label name")
+	}
+	label := p.input[start:p.pos]
+	p.skipSpaces()
+	var matchType MatchType
+	switch {
+	case p.consume(string(MatchRegexp)):
+		matchType = MatchRegexp
+	case p.consume(string(MatchNotRegexp)):
+		matchType = MatchNotRegexp
+	case p.consume(string(MatchNotEqual)):
+		matchType = MatchNotEqual
+	case p.consume(string(MatchEqual)):
+		matchType = MatchEqual
+	default:
+		return Matcher{}, p.errorf("expected a match operator after the label %q", label)
+	}
+	p.skipSpaces()
+	value, err := p.str()
+	if err != nil {
+		return Matcher{}, err
+	}
+	return Matcher{Label: label, Type: matchType, Value: value}, nil
+}
+
+func (p *selectorParser) str() (string, error) {
+	if p.pos == len(p.input) || (p.input[p.pos] != '"' && p.input[p.pos] != '`') {
+		return "", p.errorf("expected a quoted value")
+	}
+	quote := p.input[p.pos]
+	end := p.pos + 1
+	for ; end < len(p.input) && p.input[end] != quote; end++ {
+		if quote == '"' && p.input[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(p.input) {
+		return "", p.errorf("unterminated string")
+	}
+	value, err := strconv.Unquote(p.input[p.pos : end+1])
+	if err != nil {
+		return "", p.errorf("invalid string %s", p.input[p.pos:end+1])
+	}
+	p.pos = end + 1
+	return value, nil
+}
+
+func (p *selectorParser) consume(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func isLabelRune(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
@@ -0,0 +1,119 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStreamSelector(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		selector string
+		expected []Matcher
+	}{
+		{title: "empty", selector: `  `},
+		{title: "no matcher", selector: `{ }`},
+		{
+			title:    "every operator",
+			selector: `{app="api",env!="dev", pod=~"api-.*" , level!~"debug|trace"}`,
+			expected: []Matcher{Eq("app", "api"), Neq("env", "dev"), Re("pod", "api-.*"), NotRe("level", "debug|trace")},
+		},
+		{
+			title:    "escaped and raw strings",
+			selector: "{path=\"C:\\\\logs\", url=~`/api/\\d+`}",
+			expected: []Matcher{Eq("path", `C:\logs`), Re("url", `/api/\d+`)},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			actual, err := ParseStreamSelector(test.selector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("unexpected matchers:\n got: %v\nwant: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseStreamSelectorErrors(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		selector string
+	}{
+		{title: "missing braces", selector: `app="api"`},
+		{title: "unquoted value", selector: `{app=api}`},
+		{title: "unknown operator", selector: `{app=="api"}`},
+		{title: "missing comma", selector: `{app="api" env="dev"}`},
+		{title: "unterminated string", selector: `{app="api}`},
+		{title: "pipeline", selector: `{app="api"} |= "error"`},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if _, err := ParseStreamSelector(test.selector); err == nil {
+				t.Errorf("expected an error for %s", test.selector)
+			}
+		})
+	}
+}
+
+func TestMergeMatchers(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		selector string
+		matchers []Matcher
+		expected string
+	}{
+		{
+			title:    "empty selector",
+			selector: `{}`,
+			matchers: []Matcher{Re("env", "$env")},
+			expected: `{env=~"$env"}`,
+		},
+		{
+			title:    "added after the existing matchers",
+			selector: `{job="loki"}`,
+			matchers: []Matcher{Re("env", "$env"), Re("pod", "$pod")},
+			expected: `{job="loki", env=~"$env", pod=~"$pod"}`,
+		},
+		{
+			title:    "existing label kept",
+			selector: `{env="prod"}`,
+			matchers: []Matcher{Re("env", "$env"), Re("pod", "$pod"), Re("pod", "$other")},
+			expected: `{env="prod", pod=~"$pod"}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			actual, err := MergeMatchers(test.selector, test.matchers...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("unexpected selector:\n got: %s\nwant: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestMergeMatchersWithoutMatcher(t *testing.T) {
+	for _, selector := range []string{``, `{}`} {
+		if actual, err := MergeMatchers(selector); err == nil {
+			t.Errorf("expected an error for the selector %q without matcher, got %s", selector, actual)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelnames
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+func variable(name string) v1.Variable {
+	var v v1.Variable
+	v.Metadata.Name = name
+	return v
+}
+
+func TestApplyFilters(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		matchers []string
+		filters  []v1.Variable
+		expected []string
+	}{
+		{
+			title:    "no filter",
+			matchers: []string{`{job="loki"}`},
+			expected: []string{`{job="loki"}`},
+		},
+		{
+			title:    "no matcher",
+			filters:  []v1.Variable{variable("namespace"), variable("app")},
+			expected: []string{`{namespace=~"$namespace", app=~"$app"}`},
+		},
+		{
+			title:    "merged into the stream selectors",
+			matchers: []string{`{}`, `{job="loki", namespace="default"}`},
+			filters:  []v1.Variable{variable("namespace"), variable("app")},
+			expected: []string{`{namespace=~"$namespace", app=~"$app"}`, `{job="loki", namespace="default", app=~"$app"}`},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			b := &Builder{
+				PluginSpec: PluginSpec{Matchers: test.matchers},
+				Filters:    test.filters,
+			}
+			if err := b.ApplyFilters(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(b.Matchers, test.expected) {
+				t.Errorf("unexpected matchers:\n got: %v\nwant: %v", b.Matchers, test.expected)
+			}
+		})
+	}
+}
+
+func TestLokiLabelNames(t *testing.T) {
+	builder := &listvariable.Builder{Filters: []v1.Variable{variable("env")}}
+	option := LokiLabelNames(Datasource("loki"), AddMatcher(`{job="loki"}`))
+	if err := option(builder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if builder.ListVariableSpec.Plugin.Kind != PluginKind {
+		t.Fatalf("unexpected kind: %s", builder.ListVariableSpec.Plugin.Kind)
+	}
+	raw, err := json.Marshal(builder.ListVariableSpec.Plugin.Spec)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"datasource":{"kind":"LokiDatasource","name":"loki"},"matchers":["{job=\"loki\", env=~\"$env\"}"]}`
+	var actual, want any
+	if err := json.Unmarshal(raw, &actual); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(actual, want) {
+		t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, expected)
+	}
+}
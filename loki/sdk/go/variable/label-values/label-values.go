@@ -0,0 +1,115 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelvalues
+
+import (
+	"fmt"
+
+	"github.com/perses/perses/go-sdk/datasource"
+	list_variable "github.com/perses/perses/go-sdk/variable/list-variable"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/plugins/loki/sdk/go/query/logql"
+)
+
+const PluginKind = "LokiLabelValuesVariable"
+
+type PluginSpec struct {
+	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
+	LabelName  string               `json:"labelName" yaml:"labelName"`
+	Matchers   []string             `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+}
+
+func (s *PluginSpec) validate() error {
+	if len(s.LabelName) == 0 {
+		return fmt.Errorf("labelName cannot be empty")
+	}
+	return nil
+}
+
+type Option func(plugin *Builder) error
+
+func create(labelName string, options ...Option) (Builder, error) {
+	var builder = &Builder{
+		PluginSpec: PluginSpec{},
+	}
+
+	defaults := []Option{
+		LabelName(labelName),
+	}
+
+	for _, opt := range append(defaults, options...) {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+
+	if err := builder.validate(); err != nil {
+		return *builder, err
+	}
+
+	if err := builder.ApplyFilters(); err != nil {
+		return *builder, err
+	}
+
+	return *builder, nil
+}
+
+func LokiLabelValues(labelName string, options ...Option) list_variable.Option {
+	return func(builder *list_variable.Builder) error {
+		options = append([]Option{Filter(builder.Filters...)}, options...)
+		t, err := create(labelName, options...)
+		if err != nil {
+			return err
+		}
+		builder.ListVariableSpec.Plugin.Kind = PluginKind
+		builder.ListVariableSpec.Plugin.Spec = t
+		return nil
+	}
+}
+
+type Builder struct {
+	PluginSpec `json:",inline" yaml:",inline"`
+	Filters    []v1.Variable `json:"-" yaml:"-"`
+}
+
+// ApplyFilters adds a matcher on every filter variable to each stream selector, e.g. `{job="loki"}` filtered by the
+// variable `env` gives `{job="loki", env=~"$env"}`. The labels already filtered by a selector are kept untouched.
+func (b *Builder) ApplyFilters() error {
+	var matchers []logql.Matcher
+	for _, variable := range b.Filters {
+		name := variable.Metadata.Name
+		// the variable doesn't filter its own values
+		if name == b.LabelName {
+			continue
+		}
+		matchers = append(matchers, logql.Re(name, "$"+name))
+	}
+	if len(matchers) == 0 {
+		return nil
+	}
+
+	if len(b.Matchers) == 0 {
+		b.Matchers = []string{logql.Stream(matchers[0], matchers[1:]...).String()}
+		return nil
+	}
+
+	for index, selector := range b.Matchers {
+		merged, err := logql.MergeMatchers(selector, matchers...)
+		if err != nil {
+			return err
+		}
+		b.Matchers[index] = merged
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelvalues
+
+import (
+	"reflect"
+	"testing"
+
+	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+func variable(name string) v1.Variable {
+	var v v1.Variable
+	v.Metadata.Name = name
+	return v
+}
+
+func TestApplyFilters(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		labelName string
+		matchers  []string
+		filters   []v1.Variable
+		expected  []string
+	}{
+		{
+			title:    "no filter",
+			matchers: []string{`{}`},
+			expected: []string{`{}`},
+		},
+		{
+			title:    "no matcher",
+			filters:  []v1.Variable{variable("namespace"), variable("app")},
+			expected: []string{`{namespace=~"$namespace", app=~"$app"}`},
+		},
+		{
+			title:    "empty stream selector",
+			matchers: []string{`{ }`, ``},
+			filters:  []v1.Variable{variable("namespace")},
+			expected: []string{`{namespace=~"$namespace"}`, `{namespace=~"$namespace"}`},
+		},
+		{
+			title:    "merged into the stream selector",
+			matchers: []string{`{job="loki"}`, `{ env = "prod" , pod=~` + "`api-.*`" + ` }`},
+			filters:  []v1.Variable{variable("namespace"), variable("env")},
+			expected: []string{`{job="loki", namespace=~"$namespace", env=~"$env"}`, `{env="prod", pod=~"api-.*", namespace=~"$namespace"}`},
+		},
+		{
+			title:     "own label not filtered",
+			labelName: "pod",
+			matchers:  []string{`{job="loki"}`},
+			filters:   []v1.Variable{variable("pod"), variable("namespace")},
+			expected:  []string{`{job="loki", namespace=~"$namespace"}`},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			b := &Builder{
+				PluginSpec: PluginSpec{LabelName: test.labelName, Matchers: test.matchers},
+				Filters:    test.filters,
+			}
+			if err := b.ApplyFilters(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(b.Matchers, test.expected) {
+				t.Errorf("unexpected matchers:\n got: %v\nwant: %v", b.Matchers, test.expected)
+			}
+		})
+	}
+}
+
+func TestApplyFiltersInvalidSelector(t *testing.T) {
+	b := &Builder{
+		PluginSpec: PluginSpec{Matchers: []string{`{job=loki}`}},
+		Filters:    []v1.Variable{variable("namespace")},
+	}
+	if err := b.ApplyFilters(); err == nil {
+		t.Fatal("expected an error for an unquoted label value, got nil")
+	}
+}
+
+func TestLokiLabelValuesEmptyLabelName(t *testing.T) {
+	err := LokiLabelValues("", Datasource("loki"))(&listvariable.Builder{})
+	if err == nil || err.Error() != "labelName cannot be empty" {
+		t.Errorf("expected the error %q, got %v", "labelName cannot be empty", err)
+	}
+}
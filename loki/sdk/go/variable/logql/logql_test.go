@@ -0,0 +1,103 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+)
+
+func TestLokiLogQL(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		option   listvariable.Option
+		expected string
+	}{
+		{
+			title:    "expression and label",
+			option:   LokiLogQL(`{app="api"} | json`, "level"),
+			expected: `{"expr":"{app=\"api\"} | json","labelName":"level"}`,
+		},
+		{
+			title:    "with datasource",
+			option:   LokiLogQL(`{app="api"}`, "pod", Datasource("loki")),
+			expected: `{"datasource":{"kind":"LokiDatasource","name":"loki"},"expr":"{app=\"api\"}","labelName":"pod"}`,
+		},
+		{
+			title:    "options override the constructor",
+			option:   LokiLogQL(`{app="api"}`, "pod", LabelName("container"), Expr(`{app="web"}`)),
+			expected: `{"expr":"{app=\"web\"}","labelName":"container"}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder := &listvariable.Builder{}
+			if err := test.option(builder); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if builder.ListVariableSpec.Plugin.Kind != PluginKind {
+				t.Fatalf("unexpected kind: %s", builder.ListVariableSpec.Plugin.Kind)
+			}
+			raw, err := json.Marshal(builder.ListVariableSpec.Plugin.Spec)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var actual, want any
+			if err := json.Unmarshal(raw, &actual); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if err := json.Unmarshal([]byte(test.expected), &want); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(actual, want) {
+				t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, test.expected)
+			}
+		})
+	}
+}
+
+func TestLokiLogQLErrors(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		option   listvariable.Option
+		expected string
+	}{
+		{
+			title:    "empty expression",
+			option:   LokiLogQL("", "level"),
+			expected: "expr cannot be empty",
+		},
+		{
+			title:    "empty label name",
+			option:   LokiLogQL(`{app="api"}`, ""),
+			expected: "labelName cannot be empty",
+		},
+		{
+			title:    "expression emptied by an option",
+			option:   LokiLogQL(`{app="api"}`, "level", Expr("")),
+			expected: "expr cannot be empty",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			err := test.option(&listvariable.Builder{})
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("expected the error %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
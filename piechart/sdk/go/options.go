@@ -29,6 +29,7 @@ func WithLegend(legend Legend) Option {
 	}
 }
 
+// Deprecated: the visual options are not part of the PieChart schema, so Perses rejects the panels using them.
 func WithVisual(visual Visual) Option {
 	return func(builder *Builder) error {
 		builder.Visual = &visual
@@ -43,6 +44,7 @@ func WithFormat(format *common.Format) Option {
 	}
 }
 
+// Deprecated: the query settings are not part of the PieChart schema, so Perses rejects the panels using them.
 func WithQuerySettings(querySettingsList []QuerySettingsItem) Option {
 	return func(builder *Builder) error {
 		builder.QuerySettings = &querySettingsList
@@ -24,5 +24,5 @@ spec: close({
 	expr:    strings.MinRunes(1)
 	title?:  string
 	legend?: string
-	tags?: [string]
+	tags?: [...string]
 })
@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/perses/perses/go-sdk/dashboard"
+	"github.com/perses/perses/go-sdk/datasource"
+	dashboardSpec "github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/plugin"
+)
+
+const PluginKind = "PrometheusPromQLAnnotation"
+
+type PluginSpec struct {
+	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
+	Expr       string               `json:"expr" yaml:"expr"`
+	Title      string               `json:"title,omitempty" yaml:"title,omitempty"`
+	Legend     string               `json:"legend,omitempty" yaml:"legend,omitempty"`
+	Tags       []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+func (s *PluginSpec) UnmarshalJSON(data []byte) error {
+	type plain PluginSpec
+	var tmp PluginSpec
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PluginSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var tmp PluginSpec
+	type plain PluginSpec
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PluginSpec) validate() error {
+	if len(s.Expr) == 0 {
+		return fmt.Errorf("expr cannot be empty")
+	}
+	return nil
+}
+
+type Option func(plugin *Builder) error
+
+func create(expr string, options ...Option) (Builder, error) {
+	builder := &Builder{
+		PluginSpec: PluginSpec{},
+	}
+
+	defaults := []Option{
+		Expr(expr),
+	}
+
+	for _, opt := range append(defaults, options...) {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+
+	if err := builder.validate(); err != nil {
+		return *builder, err
+	}
+
+	return *builder, nil
+}
+
+type Builder struct {
+	PluginSpec `json:",inline" yaml:",inline"`
+}
+
+// Plugin returns the annotation plugin built from the given expression and options.
+// It can be used directly when the annotation list of a dashboard spec is managed by hand.
+func Plugin(expr string, options ...Option) (plugin.Plugin, error) {
+	plg, err := create(expr, options...)
+	return plugin.Plugin{
+		Kind: PluginKind,
+		Spec: plg,
+	}, err
+}
+
+// PrometheusPromQL adds a Prometheus annotation to the dashboard.
+// The perses go-sdk doesn't provide an annotation builder yet, so the annotation is appended to the dashboard spec directly.
+func PrometheusPromQL(expr string, options ...Option) dashboard.Option {
+	return func(builder *dashboard.Builder) error {
+		plg, err := Plugin(expr, options...)
+		if err != nil {
+			return err
+		}
+		builder.Dashboard.Spec.Annotations = append(builder.Dashboard.Spec.Annotations, dashboardSpec.AnnotationSpec{
+			Plugin: plg,
+		})
+		return nil
+	}
+}
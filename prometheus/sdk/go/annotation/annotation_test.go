@@ -0,0 +1,65 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAnnotationPlugin(t *testing.T) {
+	plg, err := Plugin(
+		"changes(kube_deployment_status_observed_generation[5m]) > 0",
+		Datasource("my-prom"),
+		Title("Deployment"),
+		Legend("{{deployment}}"),
+		Tags("deploy", "kubernetes"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plg.Kind != PluginKind {
+		t.Fatalf("unexpected kind: %s", plg.Kind)
+	}
+
+	raw, err := json.Marshal(plg.Spec)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"datasource":{"kind":"PrometheusDatasource","name":"my-prom"},"expr":"changes(kube_deployment_status_observed_generation[5m]) > 0","title":"Deployment","legend":"{{deployment}}","tags":["deploy","kubernetes"]}`
+	var actual, want any
+	if err := json.Unmarshal(raw, &actual); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(actual, want) {
+		t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, expected)
+	}
+}
+
+func TestAnnotationPluginRejectsEmptyExpr(t *testing.T) {
+	if _, err := Plugin(""); err == nil {
+		t.Fatal("expected error when building an annotation with an empty expr, got nil")
+	}
+}
+
+func TestPluginSpecRejectsEmptyExprOnUnmarshal(t *testing.T) {
+	var spec PluginSpec
+	if err := json.Unmarshal([]byte(`{"expr":""}`), &spec); err == nil {
+		t.Fatal("expected error unmarshalling spec with empty expr, got nil")
+	}
+}
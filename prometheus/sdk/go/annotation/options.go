@@ -0,0 +1,60 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	promDatasource "github.com/perses/plugins/prometheus/sdk/go/datasource"
+)
+
+func Expr(expr string) Option {
+	return func(builder *Builder) error {
+		builder.Expr = expr
+		return nil
+	}
+}
+
+func Datasource(datasourceName string) Option {
+	return func(builder *Builder) error {
+		builder.Datasource = promDatasource.Selector(datasourceName)
+		return nil
+	}
+}
+
+func Title(title string) Option {
+	return func(builder *Builder) error {
+		builder.Title = title
+		return nil
+	}
+}
+
+func Legend(legend string) Option {
+	return func(builder *Builder) error {
+		builder.Legend = legend
+		return nil
+	}
+}
+
+func Tags(tags ...string) Option {
+	return func(builder *Builder) error {
+		builder.Tags = tags
+		return nil
+	}
+}
+
+func AddTag(tag string) Option {
+	return func(builder *Builder) error {
+		builder.Tags = append(builder.Tags, tag)
+		return nil
+	}
+}
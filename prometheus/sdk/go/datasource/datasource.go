@@ -71,9 +71,11 @@ func (s *PluginSpec) validate() error {
 
 type Option func(plugin *Builder) error
 
-func create(options ...Option) (Builder, error) {
+func create(flavour flavour, tenants []string, options ...Option) (Builder, error) {
 	builder := &Builder{
 		PluginSpec: PluginSpec{},
+		flavour:    flavour,
+		tenants:    tenants,
 	}
 
 	var defaults []Option
@@ -84,16 +86,26 @@ func create(options ...Option) (Builder, error) {
 		}
 	}
 
+	if err := builder.applyTenants(); err != nil {
+		return *builder, err
+	}
+
 	return *builder, nil
 }
 
 type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
+	flavour    flavour
+	tenants    []string
 }
 
 func Prometheus(options ...Option) datasource.Option {
+	return newPlugin(flavourPrometheus, nil, options)
+}
+
+func newPlugin(flavour flavour, tenants []string, options []Option) datasource.Option {
 	return func(builder *datasource.Builder) error {
-		plugin, err := create(options...)
+		plugin, err := create(flavour, tenants, options...)
 		if err != nil {
 			return err
 		}
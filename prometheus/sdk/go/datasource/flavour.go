@@ -0,0 +1,156 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/perses/perses/go-sdk/datasource"
+)
+
+// TenantHeader is the HTTP header holding the tenant of the requests sent to a multi-tenant Mimir or Cortex.
+const TenantHeader = "X-Scope-OrgID"
+
+// Query parameters supported by the Thanos Query API.
+const (
+	dedupParam               = "dedup"
+	partialResponseParam     = "partial_response"
+	maxSourceResolutionParam = "max_source_resolution"
+)
+
+// flavour is the Prometheus-compatible backend queried by the datasource.
+type flavour string
+
+const (
+	flavourPrometheus flavour = "Prometheus"
+	flavourThanos     flavour = "Thanos"
+	flavourMimir      flavour = "Mimir"
+	flavourCortex     flavour = "Cortex"
+)
+
+// Resolution is the downsampling resolution of the data read by Thanos, see MaxSourceResolution.
+type Resolution string
+
+const (
+	ResolutionRaw  Resolution = "0s"
+	Resolution5m   Resolution = "5m"
+	Resolution1h   Resolution = "1h"
+	ResolutionAuto Resolution = "auto"
+)
+
+// Thanos configures a datasource querying the Query component of Thanos.
+// On top of the options of Prometheus, the Thanos options Dedup, PartialResponse and MaxSourceResolution are available.
+func Thanos(options ...Option) datasource.Option {
+	return newPlugin(flavourThanos, nil, options)
+}
+
+// Mimir configures a datasource querying Grafana Mimir for the given tenant.
+// The tenant is sent with the TenantHeader header, so the datasource must be accessed through the proxy (see HTTPProxy).
+// More tenants can be queried at once with FederatedTenants.
+func Mimir(tenant string, options ...Option) datasource.Option {
+	return newPlugin(flavourMimir, []string{tenant}, options)
+}
+
+// Cortex configures a datasource querying Cortex for the given tenant.
+// The tenant is sent with the TenantHeader header, so the datasource must be accessed through the proxy (see HTTPProxy).
+func Cortex(tenant string, options ...Option) datasource.Option {
+	return newPlugin(flavourCortex, []string{tenant}, options)
+}
+
+// Dedup enables or disables the deduplication of the series coming from replicated Prometheus, done by Thanos by
+// default. Only available with Thanos.
+func Dedup(enabled bool) Option {
+	return thanosQueryParam(dedupParam, strconv.FormatBool(enabled))
+}
+
+// PartialResponse defines whether Thanos returns a partial result when some of its store APIs are unavailable,
+// instead of failing the query. Only available with Thanos.
+func PartialResponse(enabled bool) Option {
+	return thanosQueryParam(partialResponseParam, strconv.FormatBool(enabled))
+}
+
+// MaxSourceResolution defines the maximum downsampling resolution of the data read by Thanos.
+// Only available with Thanos.
+func MaxSourceResolution(resolution Resolution) Option {
+	return func(builder *Builder) error {
+		switch resolution {
+		case ResolutionRaw, Resolution5m, Resolution1h, ResolutionAuto:
+		default:
+			return fmt.Errorf("invalid max source resolution %q, the supported values are %q, %q, %q and %q", resolution, ResolutionRaw, Resolution5m, Resolution1h, ResolutionAuto)
+		}
+		return thanosQueryParam(maxSourceResolutionParam, string(resolution))(builder)
+	}
+}
+
+// FederatedTenants adds tenants to the ones queried by the datasource, e.g. Mimir("team-a", FederatedTenants("team-b")).
+// The tenant federation must be enabled on the server. Only available with Mimir and Cortex.
+func FederatedTenants(tenants ...string) Option {
+	return func(builder *Builder) error {
+		if builder.flavour != flavourMimir && builder.flavour != flavourCortex {
+			return fmt.Errorf("the tenants are only supported by the Mimir and Cortex datasources, not by %s", builder.flavour)
+		}
+		builder.tenants = append(builder.tenants, tenants...)
+		return nil
+	}
+}
+
+func thanosQueryParam(key string, value string) Option {
+	return func(builder *Builder) error {
+		if builder.flavour != flavourThanos {
+			return fmt.Errorf("the query parameter %q is only supported by the Thanos datasource, not by %s", key, builder.flavour)
+		}
+		return QueryParam(key, value)(builder)
+	}
+}
+
+// applyTenants sets the tenant header on the proxy, once all the options have been applied.
+func (b *Builder) applyTenants() error {
+	if len(b.tenants) == 0 {
+		return nil
+	}
+	for _, tenant := range b.tenants {
+		if err := validateTenant(tenant); err != nil {
+			return err
+		}
+	}
+	if b.Proxy == nil {
+		return fmt.Errorf("the %s datasource requires the proxy to send the header %s, a direct URL cannot be used", b.flavour, TenantHeader)
+	}
+	if b.Proxy.Spec.Headers == nil {
+		b.Proxy.Spec.Headers = make(map[string]string)
+	}
+	b.Proxy.Spec.Headers[TenantHeader] = strings.Join(b.tenants, "|")
+	return nil
+}
+
+// validateTenant checks the tenant ID like Mimir and Cortex do: at most 150 characters among the alphanumeric ones
+// and `!-_.*'()`, and neither `.` nor `..`.
+func validateTenant(tenant string) error {
+	switch {
+	case tenant == "":
+		return fmt.Errorf("the tenant cannot be empty")
+	case len(tenant) > 150:
+		return fmt.Errorf("the tenant %q is longer than 150 characters", tenant)
+	case tenant == "." || tenant == "..":
+		return fmt.Errorf("the tenant %q is not allowed", tenant)
+	}
+	for _, r := range tenant {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!-_.*'()", r)) {
+			return fmt.Errorf("the tenant %q contains the unsupported character %q", tenant, r)
+		}
+	}
+	return nil
+}
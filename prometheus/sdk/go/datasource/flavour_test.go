@@ -0,0 +1,185 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlavours(t *testing.T) {
+	testSuite := []struct {
+		title       string
+		flavour     flavour
+		tenants     []string
+		options     []Option
+		queryParams map[string]string
+		headers     map[string]string
+	}{
+		{
+			title:   "thanos",
+			flavour: flavourThanos,
+			options: []Option{
+				DirectURL("http://thanos:9090"),
+				Dedup(false),
+				PartialResponse(true),
+				MaxSourceResolution(Resolution5m),
+			},
+			queryParams: map[string]string{"dedup": "false", "partial_response": "true", "max_source_resolution": "5m"},
+		},
+		{
+			title:   "mimir",
+			flavour: flavourMimir,
+			tenants: []string{"team-a"},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus")},
+			headers: map[string]string{TenantHeader: "team-a"},
+		},
+		{
+			title:   "mimir with federated tenants",
+			flavour: flavourMimir,
+			tenants: []string{"team-a"},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus"), FederatedTenants("team-b", "team_c")},
+			headers: map[string]string{TenantHeader: "team-a|team-b|team_c"},
+		},
+		{
+			title:   "cortex with federated tenants",
+			flavour: flavourCortex,
+			tenants: []string{"team-a"},
+			options: []Option{HTTPProxy("http://cortex:8080/prometheus"), FederatedTenants("team-b")},
+			headers: map[string]string{TenantHeader: "team-a|team-b"},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.flavour, test.tenants, test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(builder.QueryParams, test.queryParams) {
+				t.Errorf("unexpected query parameters: got %v, want %v", builder.QueryParams, test.queryParams)
+			}
+			var headers map[string]string
+			if builder.Proxy != nil {
+				headers = builder.Proxy.Spec.Headers
+			}
+			if !reflect.DeepEqual(headers, test.headers) {
+				t.Errorf("unexpected headers: got %v, want %v", headers, test.headers)
+			}
+		})
+	}
+}
+
+func TestFlavoursErrors(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		flavour flavour
+		tenants []string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "tenant with an unsupported character",
+			flavour: flavourMimir,
+			tenants: []string{"team/a"},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus")},
+			err:     `the tenant "team/a" contains the unsupported character '/'`,
+		},
+		{
+			title:   "empty tenant",
+			flavour: flavourCortex,
+			tenants: []string{""},
+			options: []Option{HTTPProxy("http://cortex:8080/prometheus")},
+			err:     "the tenant cannot be empty",
+		},
+		{
+			title:   "forbidden tenant",
+			flavour: flavourMimir,
+			tenants: []string{"team-a"},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus"), FederatedTenants("..")},
+			err:     `the tenant ".." is not allowed`,
+		},
+		{
+			title:   "tenant too long",
+			flavour: flavourMimir,
+			tenants: []string{strings.Repeat("a", 151)},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus")},
+			err:     "is longer than 150 characters",
+		},
+		{
+			title:   "federated tenants with thanos",
+			flavour: flavourThanos,
+			options: []Option{DirectURL("http://thanos:9090"), FederatedTenants("team-b")},
+			err:     "the tenants are only supported by the Mimir and Cortex datasources, not by Thanos",
+		},
+		{
+			title:   "federated tenants with prometheus",
+			flavour: flavourPrometheus,
+			options: []Option{DirectURL("http://prometheus:9090"), FederatedTenants("team-b")},
+			err:     "the tenants are only supported by the Mimir and Cortex datasources, not by Prometheus",
+		},
+		{
+			title:   "mimir with a direct URL",
+			flavour: flavourMimir,
+			tenants: []string{"team-a"},
+			options: []Option{DirectURL("http://mimir:8080/prometheus")},
+			err:     "the Mimir datasource requires the proxy to send the header X-Scope-OrgID, a direct URL cannot be used",
+		},
+		{
+			title:   "thanos option with mimir",
+			flavour: flavourMimir,
+			tenants: []string{"team-a"},
+			options: []Option{HTTPProxy("http://mimir:8080/prometheus"), Dedup(true)},
+			err:     `the query parameter "dedup" is only supported by the Thanos datasource, not by Mimir`,
+		},
+		{
+			title:   "invalid max source resolution",
+			flavour: flavourThanos,
+			options: []Option{DirectURL("http://thanos:9090"), MaxSourceResolution("10m")},
+			err:     `invalid max source resolution "10m"`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.flavour, test.tenants, test.options...)
+			if err == nil {
+				t.Fatalf("expected the error %q, got nil", test.err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("unexpected error: got %q, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestQueryParamsCopiesTheMap(t *testing.T) {
+	params := map[string]string{"dedup": "false"}
+	builder, err := create(flavourPrometheus, nil,
+		DirectURL("http://prometheus:9090"),
+		QueryParam("partial_response", "true"),
+		QueryParams(params),
+		AddQueryParam("max_source_resolution", "0s"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// QueryParams replaces the parameters defined before it
+	expected := map[string]string{"dedup": "false", "max_source_resolution": "0s"}
+	if !reflect.DeepEqual(builder.QueryParams, expected) {
+		t.Errorf("unexpected query parameters: got %v, want %v", builder.QueryParams, expected)
+	}
+	if !reflect.DeepEqual(params, map[string]string{"dedup": "false"}) {
+		t.Errorf("the map given to QueryParams has been modified: %v", params)
+	}
+}
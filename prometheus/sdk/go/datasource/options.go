@@ -14,7 +14,12 @@
 package datasource
 
 import (
+	"fmt"
+	"maps"
+	"time"
+
 	"github.com/perses/perses/go-sdk/http"
+	"github.com/perses/spec/go/common"
 )
 
 func DirectURL(url string) Option {
@@ -35,9 +40,10 @@ func HTTPProxy(url string, options ...http.Option) Option {
 	}
 }
 
+// QueryParams replaces the query parameters already defined by the given ones. The given map is copied.
 func QueryParams(params map[string]string) Option {
 	return func(builder *Builder) error {
-		builder.QueryParams = params
+		builder.QueryParams = maps.Clone(params)
 		return nil
 	}
 }
@@ -51,3 +57,25 @@ func QueryParam(key, value string) Option {
 		return nil
 	}
 }
+
+// AddQueryParam adds the given query parameter to the ones already defined.
+func AddQueryParam(key, value string) Option {
+	return func(builder *Builder) error {
+		if builder.QueryParams == nil {
+			builder.QueryParams = make(map[string]string)
+		}
+		builder.QueryParams[key] = value
+		return nil
+	}
+}
+
+// ScrapeInterval defines the scrape interval of the metrics, used as the default min step of the queries.
+func ScrapeInterval(interval time.Duration) Option {
+	return func(builder *Builder) error {
+		if interval <= 0 {
+			return fmt.Errorf("the scrape interval must be positive, got %s", interval)
+		}
+		builder.ScrapeInterval = common.Duration(interval)
+		return nil
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	promDatasource "github.com/perses/plugins/prometheus/sdk/go/datasource"
+	"github.com/perses/plugins/prometheus/sdk/go/query/promql"
 	"github.com/perses/spec/go/common"
 )
 
@@ -27,6 +28,11 @@ func Expr(expr string) Option {
 	}
 }
 
+// Expression defines the query expression from an expression built with the promql package.
+func Expression(expr promql.Expr) Option {
+	return Expr(expr.String())
+}
+
 func Datasource(datasourceName string) Option {
 	return func(builder *Builder) error {
 		builder.Datasource = promDatasource.Selector(datasourceName)
@@ -61,3 +67,12 @@ func Instant(instant bool) Option {
 		return nil
 	}
 }
+
+// Validate enables the validation of the expression with the Prometheus parser when the query is built.
+// The references to the variables are accepted, see promql.Validate.
+func Validate() Option {
+	return func(builder *Builder) error {
+		builder.validate = true
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+// AggregateExpr aggregates the series of a vector, e.g. `sum by (job) (up)`.
+type AggregateExpr struct {
+	operator string
+	param    Expr
+	expr     Expr
+	without  bool
+	labels   []string
+}
+
+func aggregate(operator string, param Expr, expr Expr) AggregateExpr {
+	return AggregateExpr{operator: operator, param: param, expr: expr}
+}
+
+func Sum(expr Expr) AggregateExpr {
+	return aggregate("sum", nil, expr)
+}
+
+func Avg(expr Expr) AggregateExpr {
+	return aggregate("avg", nil, expr)
+}
+
+func Min(expr Expr) AggregateExpr {
+	return aggregate("min", nil, expr)
+}
+
+func Max(expr Expr) AggregateExpr {
+	return aggregate("max", nil, expr)
+}
+
+func Count(expr Expr) AggregateExpr {
+	return aggregate("count", nil, expr)
+}
+
+func Group(expr Expr) AggregateExpr {
+	return aggregate("group", nil, expr)
+}
+
+func Stddev(expr Expr) AggregateExpr {
+	return aggregate("stddev", nil, expr)
+}
+
+func Stdvar(expr Expr) AggregateExpr {
+	return aggregate("stdvar", nil, expr)
+}
+
+// TopK keeps the k largest series, e.g. `topk(5, up)`.
+func TopK(k int, expr Expr) AggregateExpr {
+	return aggregate("topk", Number(float64(k)), expr)
+}
+
+// BottomK keeps the k smallest series, e.g. `bottomk(5, up)`.
+func BottomK(k int, expr Expr) AggregateExpr {
+	return aggregate("bottomk", Number(float64(k)), expr)
+}
+
+// Quantile computes the φ-quantile of the series, e.g. `quantile(0.9, up)`.
+func Quantile(quantile float64, expr Expr) AggregateExpr {
+	return aggregate("quantile", Number(quantile), expr)
+}
+
+// CountValues counts the series having the same value, stored in the given label, e.g. `count_values("version", build_info)`.
+func CountValues(label string, expr Expr) AggregateExpr {
+	return aggregate("count_values", Str(label), expr)
+}
+
+// By keeps the given labels in the result of the aggregation, e.g. `sum by (job, instance) (up)`.
+func (a AggregateExpr) By(labels ...string) AggregateExpr {
+	a.without = false
+	a.labels = labels
+	return a
+}
+
+// Without removes the given labels from the result of the aggregation, e.g. `sum without (instance) (up)`.
+func (a AggregateExpr) Without(labels ...string) AggregateExpr {
+	a.without = true
+	a.labels = labels
+	return a
+}
+
+func (a AggregateExpr) String() string {
+	result := a.operator
+	switch {
+	case a.without:
+		result += " without (" + joinLabels(a.labels) + ") "
+	case len(a.labels) > 0:
+		result += " by (" + joinLabels(a.labels) + ") "
+	}
+	result += "("
+	if a.param != nil {
+		result += a.param.String() + ", "
+	}
+	return result + a.expr.String() + ")"
+}
+
+func (a AggregateExpr) precedence() int {
+	return precedencePrimary
+}
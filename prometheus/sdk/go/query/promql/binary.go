@@ -0,0 +1,192 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+type vectorMatching struct {
+	on     bool
+	labels []string
+	// group is "left" or "right" for a many-to-one or one-to-many matching
+	group         string
+	includeLabels []string
+}
+
+// BinaryExpr combines two expressions with an operator, e.g. `a / on (job) b`.
+type BinaryExpr struct {
+	operator   string
+	lhs        Expr
+	rhs        Expr
+	returnBool bool
+	matching   *vectorMatching
+}
+
+func binary(operator string, lhs Expr, rhs Expr) BinaryExpr {
+	return BinaryExpr{operator: operator, lhs: lhs, rhs: rhs}
+}
+
+func Add(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("+", lhs, rhs)
+}
+
+func Sub(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("-", lhs, rhs)
+}
+
+func Mul(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("*", lhs, rhs)
+}
+
+func Div(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("/", lhs, rhs)
+}
+
+func Mod(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("%", lhs, rhs)
+}
+
+func Pow(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("^", lhs, rhs)
+}
+
+func Atan2(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("atan2", lhs, rhs)
+}
+
+func Equal(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("==", lhs, rhs)
+}
+
+func NotEqual(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("!=", lhs, rhs)
+}
+
+func GreaterThan(lhs Expr, rhs Expr) BinaryExpr {
+	return binary(">", lhs, rhs)
+}
+
+func GreaterOrEqual(lhs Expr, rhs Expr) BinaryExpr {
+	return binary(">=", lhs, rhs)
+}
+
+func LessThan(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("<", lhs, rhs)
+}
+
+func LessOrEqual(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("<=", lhs, rhs)
+}
+
+func And(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("and", lhs, rhs)
+}
+
+func Or(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("or", lhs, rhs)
+}
+
+func Unless(lhs Expr, rhs Expr) BinaryExpr {
+	return binary("unless", lhs, rhs)
+}
+
+// Bool makes a comparison return 0 or 1 instead of filtering the series, e.g. `up == bool 0`.
+func (b BinaryExpr) Bool() BinaryExpr {
+	b.returnBool = true
+	return b
+}
+
+// On matches the series of both sides on the given labels only, e.g. `a / on (job) b`.
+func (b BinaryExpr) On(labels ...string) BinaryExpr {
+	b.matching = b.cloneMatching()
+	b.matching.on = true
+	b.matching.labels = labels
+	return b
+}
+
+// Ignoring matches the series of both sides on all their labels except the given ones, e.g. `a / ignoring (code) b`.
+func (b BinaryExpr) Ignoring(labels ...string) BinaryExpr {
+	b.matching = b.cloneMatching()
+	b.matching.on = false
+	b.matching.labels = labels
+	return b
+}
+
+// GroupLeft allows many series of the left side to match one series of the right side.
+// The given labels of the right side are copied into the result, e.g. `a * on (pod) group_left (node) b`.
+func (b BinaryExpr) GroupLeft(labels ...string) BinaryExpr {
+	b.matching = b.cloneMatching()
+	b.matching.group = "left"
+	b.matching.includeLabels = labels
+	return b
+}
+
+// GroupRight allows many series of the right side to match one series of the left side.
+// The given labels of the left side are copied into the result.
+func (b BinaryExpr) GroupRight(labels ...string) BinaryExpr {
+	b.matching = b.cloneMatching()
+	b.matching.group = "right"
+	b.matching.includeLabels = labels
+	return b
+}
+
+func (b BinaryExpr) cloneMatching() *vectorMatching {
+	if b.matching == nil {
+		return &vectorMatching{}
+	}
+	clone := *b.matching
+	return &clone
+}
+
+func (b BinaryExpr) String() string {
+	precedence := b.precedence()
+	rightAssociative := b.operator == "^"
+	lhs := b.lhs.String()
+	if p := b.lhs.precedence(); p < precedence || (p == precedence && rightAssociative) {
+		lhs = "(" + lhs + ")"
+	}
+	rhs := b.rhs.String()
+	if p := b.rhs.precedence(); p < precedence || (p == precedence && !rightAssociative) {
+		rhs = "(" + rhs + ")"
+	}
+	result := lhs + " " + b.operator
+	if b.returnBool {
+		result += " bool"
+	}
+	if m := b.matching; m != nil {
+		// a grouping modifier is only valid after `on` or `ignoring`
+		if m.on {
+			result += " on (" + joinLabels(m.labels) + ")"
+		} else {
+			result += " ignoring (" + joinLabels(m.labels) + ")"
+		}
+		if m.group != "" {
+			result += " group_" + m.group + " (" + joinLabels(m.includeLabels) + ")"
+		}
+	}
+	return result + " " + rhs
+}
+
+func (b BinaryExpr) precedence() int {
+	switch b.operator {
+	case "or":
+		return precedenceOr
+	case "and", "unless":
+		return precedenceAnd
+	case "==", "!=", ">", ">=", "<", "<=":
+		return precedenceComparison
+	case "+", "-":
+		return precedenceAdditive
+	case "^":
+		return precedencePower
+	}
+	return precedenceMultiplicative
+}
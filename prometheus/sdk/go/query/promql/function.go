@@ -0,0 +1,208 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import "strings"
+
+// FunctionCall calls a PromQL function, e.g. `rate(http_requests_total[5m])`.
+type FunctionCall struct {
+	name string
+	args []Expr
+}
+
+// Call calls any PromQL function. Prefer the typed helpers (Rate, HistogramQuantile...) when they exist.
+func Call(name string, args ...Expr) FunctionCall {
+	return FunctionCall{name: name, args: args}
+}
+
+func (f FunctionCall) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = arg.String()
+	}
+	return f.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (f FunctionCall) precedence() int {
+	return precedencePrimary
+}
+
+// Functions over a range vector.
+
+func Rate(m MatrixSelector) FunctionCall {
+	return Call("rate", m)
+}
+
+func Irate(m MatrixSelector) FunctionCall {
+	return Call("irate", m)
+}
+
+func Increase(m MatrixSelector) FunctionCall {
+	return Call("increase", m)
+}
+
+func Delta(m MatrixSelector) FunctionCall {
+	return Call("delta", m)
+}
+
+func Idelta(m MatrixSelector) FunctionCall {
+	return Call("idelta", m)
+}
+
+func Deriv(m MatrixSelector) FunctionCall {
+	return Call("deriv", m)
+}
+
+func Changes(m MatrixSelector) FunctionCall {
+	return Call("changes", m)
+}
+
+func Resets(m MatrixSelector) FunctionCall {
+	return Call("resets", m)
+}
+
+func AvgOverTime(m MatrixSelector) FunctionCall {
+	return Call("avg_over_time", m)
+}
+
+func MinOverTime(m MatrixSelector) FunctionCall {
+	return Call("min_over_time", m)
+}
+
+func MaxOverTime(m MatrixSelector) FunctionCall {
+	return Call("max_over_time", m)
+}
+
+func SumOverTime(m MatrixSelector) FunctionCall {
+	return Call("sum_over_time", m)
+}
+
+func CountOverTime(m MatrixSelector) FunctionCall {
+	return Call("count_over_time", m)
+}
+
+func LastOverTime(m MatrixSelector) FunctionCall {
+	return Call("last_over_time", m)
+}
+
+func StddevOverTime(m MatrixSelector) FunctionCall {
+	return Call("stddev_over_time", m)
+}
+
+func QuantileOverTime(quantile float64, m MatrixSelector) FunctionCall {
+	return Call("quantile_over_time", Number(quantile), m)
+}
+
+func AbsentOverTime(m MatrixSelector) FunctionCall {
+	return Call("absent_over_time", m)
+}
+
+// Functions over an instant vector.
+
+func Abs(expr Expr) FunctionCall {
+	return Call("abs", expr)
+}
+
+func Ceil(expr Expr) FunctionCall {
+	return Call("ceil", expr)
+}
+
+func Floor(expr Expr) FunctionCall {
+	return Call("floor", expr)
+}
+
+func Round(expr Expr) FunctionCall {
+	return Call("round", expr)
+}
+
+func Sqrt(expr Expr) FunctionCall {
+	return Call("sqrt", expr)
+}
+
+func Ln(expr Expr) FunctionCall {
+	return Call("ln", expr)
+}
+
+func Log2(expr Expr) FunctionCall {
+	return Call("log2", expr)
+}
+
+func Log10(expr Expr) FunctionCall {
+	return Call("log10", expr)
+}
+
+func Exp(expr Expr) FunctionCall {
+	return Call("exp", expr)
+}
+
+func Absent(expr Expr) FunctionCall {
+	return Call("absent", expr)
+}
+
+func Sort(expr Expr) FunctionCall {
+	return Call("sort", expr)
+}
+
+func SortDesc(expr Expr) FunctionCall {
+	return Call("sort_desc", expr)
+}
+
+func Timestamp(expr Expr) FunctionCall {
+	return Call("timestamp", expr)
+}
+
+func Scalar(expr Expr) FunctionCall {
+	return Call("scalar", expr)
+}
+
+func Vector(value float64) FunctionCall {
+	return Call("vector", Number(value))
+}
+
+func Time() FunctionCall {
+	return Call("time")
+}
+
+func ClampMin(expr Expr, minValue float64) FunctionCall {
+	return Call("clamp_min", expr, Number(minValue))
+}
+
+func ClampMax(expr Expr, maxValue float64) FunctionCall {
+	return Call("clamp_max", expr, Number(maxValue))
+}
+
+func Clamp(expr Expr, minValue float64, maxValue float64) FunctionCall {
+	return Call("clamp", expr, Number(minValue), Number(maxValue))
+}
+
+// HistogramQuantile computes the φ-quantile of the buckets of a classic histogram,
+// e.g. `histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))`.
+func HistogramQuantile(quantile float64, expr Expr) FunctionCall {
+	return Call("histogram_quantile", Number(quantile), expr)
+}
+
+// LabelReplace writes into dst the replacement of the regular expression matched against the src label,
+// e.g. `label_replace(up, "host", "$1", "instance", "(.*):.*")`.
+func LabelReplace(expr Expr, dst string, replacement string, src string, regex string) FunctionCall {
+	return Call("label_replace", expr, Str(dst), Str(replacement), Str(src), Str(regex))
+}
+
+// LabelJoin writes into dst the values of the src labels joined with the separator.
+func LabelJoin(expr Expr, dst string, separator string, src ...string) FunctionCall {
+	args := []Expr{expr, Str(dst), Str(separator)}
+	for _, label := range src {
+		args = append(args, Str(label))
+	}
+	return Call("label_join", args...)
+}
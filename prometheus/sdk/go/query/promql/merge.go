@@ -0,0 +1,111 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// metricVariable matches a series selector whose metric name is a variable, e.g. `$metric{job="api"}`.
+var metricVariable = regexp.MustCompile(`^(\$\w+|\$\{[^}]+\})(\{.*\})?$`)
+
+// bareMetric matches a metric name written without quotes, the dots of the OpenTelemetry metrics being accepted.
+var bareMetric = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.]*$`)
+
+// MergeMatchers adds the matchers to the series selector, e.g. `up{job="api"}` merged with `env=~"$env"` gives
+// `up{job="api", env=~"$env"}`. The labels already filtered by the selector are kept untouched: the matchers on them
+// are skipped, like the ones on a label appearing twice in the matchers.
+// The selector is parsed with the Prometheus parser. A metric name with dots is accepted without quotes
+// (e.g. `http.server.duration`), as well as a variable used as metric name (e.g. `$metric`). A selector using variables
+// the parser rejects (e.g. `up{$label="api"}`) cannot be merged and is returned unchanged.
+func MergeMatchers(selector string, matchers ...Matcher) (string, error) {
+	selector = strings.TrimSpace(selector)
+	var metricPrefix string
+	var existing []Matcher
+	if groups := metricVariable.FindStringSubmatch(selector); groups != nil {
+		metricPrefix = groups[1]
+		if groups[2] != "" && groups[2] != "{}" {
+			parsed, err := parseSelector(groups[2])
+			switch {
+			case err == nil:
+				existing = parsed
+			case strings.Contains(groups[2], "$"):
+				return selector, nil
+			default:
+				return "", err
+			}
+		}
+	} else {
+		parsed, err := parseSelector(selector)
+		switch {
+		case err == nil:
+			existing = parsed
+		case bareMetric.MatchString(selector):
+			// a bare metric name with dots, like the OpenTelemetry metrics
+			existing = []Matcher{Eq(labels.MetricName, selector)}
+		case strings.Contains(selector, "$"):
+			return selector, nil
+		default:
+			return "", err
+		}
+	}
+
+	filtered := make(map[string]bool, len(existing)+len(matchers))
+	for _, m := range existing {
+		filtered[m.Label] = true
+	}
+	result := existing
+	for _, m := range matchers {
+		if filtered[m.Label] {
+			continue
+		}
+		filtered[m.Label] = true
+		result = append(result, m)
+	}
+
+	if metricPrefix != "" {
+		if len(result) == 0 {
+			return metricPrefix, nil
+		}
+		formatted := make([]string, len(result))
+		for i, m := range result {
+			formatted[i] = m.String()
+		}
+		return metricPrefix + "{" + strings.Join(formatted, ", ") + "}", nil
+	}
+	s := Selector(result...)
+	for i, m := range result {
+		if m.Label == labels.MetricName && m.Type == MatchEqual {
+			s = Metric(m.Value, append(append([]Matcher{}, result[:i]...), result[i+1:]...)...)
+			break
+		}
+	}
+	return s.String(), nil
+}
+
+func parseSelector(selector string) ([]Matcher, error) {
+	parsed, err := promqlParser.ParseMetricSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid series selector %q: %w", selector, err)
+	}
+	result := make([]Matcher, len(parsed))
+	for i, m := range parsed {
+		result[i] = Matcher{Label: m.Name, Type: MatchType(m.Type.String()), Value: m.Value}
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import "testing"
+
+func TestMergeMatchers(t *testing.T) {
+	filters := []Matcher{Re("namespace", "$namespace"), Eq("env", "${env:raw}")}
+	testSuites := []struct {
+		title    string
+		selector string
+		expected string
+	}{
+		{
+			title:    "metric only",
+			selector: "kube_pod_info",
+			expected: `kube_pod_info{namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "existing matchers",
+			selector: `up{job="api",pod!~'test.*'}`,
+			expected: `up{job="api", pod!~"test.*", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "label already filtered",
+			selector: `up{namespace="kube-system"}`,
+			expected: `up{namespace="kube-system", env="${env:raw}"}`,
+		},
+		{
+			title:    "selector without metric",
+			selector: `{job="api"}`,
+			expected: `{job="api", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "quoted metric",
+			selector: `{"http.server.duration"}`,
+			expected: `{"http.server.duration", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "bare metric with special characters",
+			selector: `http.server.duration`,
+			expected: `{"http.server.duration", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "bare metric with colons",
+			selector: `job:http_requests:rate5m`,
+			expected: `job:http_requests:rate5m{namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "variable as label name",
+			selector: `up{$label="api"}`,
+			expected: `up{$label="api"}`,
+		},
+		{
+			title:    "variable as metric and as label name",
+			selector: `$metric{$label="api"}`,
+			expected: `$metric{$label="api"}`,
+		},
+		{
+			title:    "variable as metric",
+			selector: `$metric{job="api"}`,
+			expected: `$metric{job="api", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+		{
+			title:    "regex on the metric name",
+			selector: `{__name__=~"up|scrape_.*"}`,
+			expected: `{__name__=~"up|scrape_.*", namespace=~"$namespace", env="${env:raw}"}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			actual, err := MergeMatchers(test.selector, filters...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("unexpected selector:\n got: %s\nwant: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestMergeMatchersDuplicates(t *testing.T) {
+	actual, err := MergeMatchers("up", Re("job", "$job"), Re("job", "$job"), Eq("job", "api"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `up{job=~"$job"}`; actual != expected {
+		t.Errorf("unexpected selector:\n got: %s\nwant: %s", actual, expected)
+	}
+}
+
+func TestMergeMatchersError(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		selector string
+	}{
+		{
+			title:    "unbalanced braces",
+			selector: `up{job="api"`,
+		},
+		{
+			title:    "expression",
+			selector: `rate(up[5m])`,
+		},
+		{
+			title:    "aggregation",
+			selector: `sum by (job) (up)`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if actual, err := MergeMatchers(test.selector, Re("job", "$job")); err == nil {
+				t.Fatalf("expected an error for an invalid selector, got %s", actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promql builds PromQL expressions from typed Go values.
+//
+// The expressions are rendered with the canonical formatting of Prometheus (e.g. `sum by (job) (rate(up[5m]))`), so
+// they can be given directly to the query plugin:
+//
+//	query.PromQL(promql.Sum(promql.Rate(promql.Metric("http_requests_total").Range(5*time.Minute))).By("job").String())
+package promql
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Precedence of the expressions, used to add the parentheses required when rendering a binary operation.
+// The values follow the PromQL grammar, from the loosest to the tightest binding.
+const (
+	precedenceOr = iota + 1
+	precedenceAnd
+	precedenceComparison
+	precedenceAdditive
+	precedenceMultiplicative
+	precedenceUnary
+	precedencePower
+	precedencePrimary
+)
+
+// Expr is a PromQL expression.
+type Expr interface {
+	// String returns the expression in the canonical PromQL format.
+	String() string
+	precedence() int
+}
+
+type numberLiteral float64
+
+// Number returns a number literal, e.g. `0.99`.
+func Number(value float64) Expr {
+	return numberLiteral(value)
+}
+
+func (n numberLiteral) String() string {
+	value := float64(n)
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func (n numberLiteral) precedence() int {
+	// a negative number is parsed as the unary minus applied to the number
+	if float64(n) < 0 || math.IsInf(float64(n), -1) {
+		return precedenceUnary
+	}
+	return precedencePrimary
+}
+
+type stringLiteral string
+
+// Str returns a string literal, e.g. the label arguments of `label_replace`.
+func Str(value string) Expr {
+	return stringLiteral(value)
+}
+
+func (s stringLiteral) String() string {
+	return strconv.Quote(string(s))
+}
+
+func (s stringLiteral) precedence() int {
+	return precedencePrimary
+}
+
+type parenExpr struct {
+	expr Expr
+}
+
+// Paren wraps the expression in parentheses.
+// Parentheses are added automatically where the precedence of the operators requires them, so Paren is only needed
+// to make the grouping explicit.
+func Paren(expr Expr) Expr {
+	return parenExpr{expr: expr}
+}
+
+func (p parenExpr) String() string {
+	return "(" + p.expr.String() + ")"
+}
+
+func (p parenExpr) precedence() int {
+	return precedencePrimary
+}
+
+var durationUnits = []struct {
+	unit     string
+	duration time.Duration
+}{
+	{unit: "y", duration: 365 * 24 * time.Hour},
+	{unit: "w", duration: 7 * 24 * time.Hour},
+	{unit: "d", duration: 24 * time.Hour},
+	{unit: "h", duration: time.Hour},
+	{unit: "m", duration: time.Minute},
+	{unit: "s", duration: time.Second},
+	{unit: "ms", duration: time.Millisecond},
+}
+
+// formatDuration renders a duration like Prometheus does, e.g. `1h30m`. The precision is the millisecond.
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	rest := d
+	for _, u := range durationUnits {
+		if rest >= u.duration {
+			b.WriteString(strconv.FormatInt(int64(rest/u.duration), 10))
+			b.WriteString(u.unit)
+			rest %= u.duration
+		}
+	}
+	if rest == d {
+		// nothing has been written, the duration is lower than the precision
+		return "0s"
+	}
+	return b.String()
+}
+
+func joinLabels(labels []string) string {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = formatLabelName(label)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// formatLabelName returns the label name, quoted when it isn't a valid legacy name (e.g. `"service.name"`).
+func formatLabelName(name string) string {
+	if isLegacyName(name, false) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// isLegacyName tells whether the name matches `[a-zA-Z_][a-zA-Z0-9_]*`, with colons allowed for the metric names.
+func isLegacyName(name string, allowColons bool) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		case r == ':' && allowColons:
+		default:
+			return false
+		}
+	}
+	return true
+}
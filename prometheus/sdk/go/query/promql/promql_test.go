@@ -0,0 +1,171 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestExprString(t *testing.T) {
+	requests := Metric("http_requests_total", Eq("job", "api"))
+	testSuites := []struct {
+		title    string
+		expr     Expr
+		expected string
+	}{
+		{
+			title:    "metric only",
+			expr:     Metric("up"),
+			expected: `up`,
+		},
+		{
+			title:    "metric with matchers",
+			expr:     Metric("up", Eq("job", "api"), Neq("env", "dev"), Re("pod", "$pod"), NotRe("mode", "idle|iowait")),
+			expected: `up{job="api", env!="dev", pod=~"$pod", mode!~"idle|iowait"}`,
+		},
+		{
+			title:    "selector without metric",
+			expr:     Selector(Eq("__name__", "up")),
+			expected: `{__name__="up"}`,
+		},
+		{
+			title:    "quoted names",
+			expr:     Metric("http.server.duration", Eq("service.name", `say "hi"`)),
+			expected: `{"http.server.duration", "service.name"="say \"hi\""}`,
+		},
+		{
+			title:    "where doesn't alter the original selector",
+			expr:     requests.Where(Eq("code", "500")),
+			expected: `http_requests_total{job="api", code="500"}`,
+		},
+		{
+			title:    "range and offset",
+			expr:     requests.Range(90 * time.Second).Offset(24 * time.Hour),
+			expected: `http_requests_total{job="api"}[1m30s] offset 1d`,
+		},
+		{
+			title:    "range variable",
+			expr:     Rate(Metric("http_requests_total").RangeVar("__rate_interval")),
+			expected: `rate(http_requests_total[$__rate_interval])`,
+		},
+		{
+			title:    "aggregation by",
+			expr:     Sum(Rate(requests.Range(5*time.Minute))).By("job", "instance"),
+			expected: `sum by (job, instance) (rate(http_requests_total{job="api"}[5m]))`,
+		},
+		{
+			title:    "aggregation without",
+			expr:     Max(Metric("up")).Without("instance"),
+			expected: `max without (instance) (up)`,
+		},
+		{
+			title:    "aggregation with parameter",
+			expr:     TopK(5, Metric("up")).By("job"),
+			expected: `topk by (job) (5, up)`,
+		},
+		{
+			title:    "count values",
+			expr:     CountValues("version", Metric("build_info")),
+			expected: `count_values("version", build_info)`,
+		},
+		{
+			title:    "histogram quantile",
+			expr:     HistogramQuantile(0.99, Sum(Rate(Metric("http_request_duration_seconds_bucket").Range(5*time.Minute))).By("le")),
+			expected: `histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))`,
+		},
+		{
+			title:    "label replace",
+			expr:     LabelReplace(Metric("up"), "host", "$1", "instance", "(.*):.*"),
+			expected: `label_replace(up, "host", "$1", "instance", "(.*):.*")`,
+		},
+		{
+			title:    "binary with matching",
+			expr:     Mul(Metric("kube_pod_info"), Metric("node_load1")).On("node").GroupLeft("pod"),
+			expected: `kube_pod_info * on (node) group_left (pod) node_load1`,
+		},
+		{
+			title:    "grouping without on",
+			expr:     Div(Metric("a"), Metric("b")).GroupRight(),
+			expected: `a / ignoring () group_right () b`,
+		},
+		{
+			title:    "bool comparison",
+			expr:     Equal(Metric("up"), Number(0)).Bool(),
+			expected: `up == bool 0`,
+		},
+		{
+			title:    "precedence",
+			expr:     Mul(Add(Metric("a"), Metric("b")), Sub(Metric("c"), Div(Metric("d"), Number(2)))),
+			expected: `(a + b) * (c - d / 2)`,
+		},
+		{
+			title:    "left associativity",
+			expr:     Sub(Sub(Metric("a"), Metric("b")), Sub(Metric("c"), Metric("d"))),
+			expected: `a - b - (c - d)`,
+		},
+		{
+			title:    "right associativity",
+			expr:     Pow(Pow(Metric("a"), Metric("b")), Pow(Metric("c"), Number(-1))),
+			expected: `(a ^ b) ^ c ^ (-1)`,
+		},
+		{
+			title:    "negative number as power base",
+			expr:     Pow(Number(-2), Number(2)),
+			expected: `(-2) ^ 2`,
+		},
+		{
+			title:    "set operators",
+			expr:     Or(And(Metric("a"), Metric("b")), Unless(Metric("c"), Metric("d"))),
+			expected: `a and b or c unless d`,
+		},
+		{
+			title:    "explicit parentheses",
+			expr:     Paren(Metric("up")),
+			expected: `(up)`,
+		},
+		{
+			title:    "special numbers",
+			expr:     Clamp(Metric("up"), math.Inf(-1), math.Inf(1)),
+			expected: `clamp(up, -Inf, +Inf)`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if actual := test.expr.String(); actual != test.expected {
+				t.Errorf("unexpected expression:\n got: %s\nwant: %s", actual, test.expected)
+			}
+		})
+	}
+	if actual := requests.String(); actual != `http_requests_total{job="api"}` {
+		t.Errorf("the original selector has been modified: %s", actual)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for duration, expected := range map[time.Duration]string{
+		0:                                 "0s",
+		time.Microsecond:                  "0s",
+		1500 * time.Millisecond:           "1s500ms",
+		2 * time.Hour:                     "2h",
+		-5 * time.Minute:                  "-5m",
+		8 * 24 * time.Hour:                "1w1d",
+		365*24*time.Hour + 30*time.Minute: "1y30m",
+	} {
+		if actual := formatDuration(duration); actual != expected {
+			t.Errorf("formatDuration(%s) = %s, want %s", duration, actual, expected)
+		}
+	}
+}
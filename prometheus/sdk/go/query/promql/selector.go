@@ -0,0 +1,155 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher filters the series of a selector on the value of a label.
+type Matcher struct {
+	Label string
+	Type  MatchType
+	Value string
+}
+
+// Eq matches the series whose label is equal to the value, e.g. `job="api"`.
+func Eq(label string, value string) Matcher {
+	return Matcher{Label: label, Type: MatchEqual, Value: value}
+}
+
+// Neq matches the series whose label is not equal to the value, e.g. `job!="api"`.
+func Neq(label string, value string) Matcher {
+	return Matcher{Label: label, Type: MatchNotEqual, Value: value}
+}
+
+// Re matches the series whose label matches the regular expression, e.g. `pod=~"$pod"`.
+// Use it with the multi-value variables, as their values are converted into a regular expression.
+func Re(label string, regexp string) Matcher {
+	return Matcher{Label: label, Type: MatchRegexp, Value: regexp}
+}
+
+// NotRe matches the series whose label doesn't match the regular expression, e.g. `mode!~"idle|iowait"`.
+func NotRe(label string, regexp string) Matcher {
+	return Matcher{Label: label, Type: MatchNotRegexp, Value: regexp}
+}
+
+func (m Matcher) String() string {
+	return formatLabelName(m.Label) + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// VectorSelector selects the series of an instant vector, e.g. `up{job="api"}`.
+type VectorSelector struct {
+	metric   string
+	matchers []Matcher
+	offset   time.Duration
+}
+
+// Metric selects the series of the metric, optionally filtered with matchers.
+func Metric(name string, matchers ...Matcher) VectorSelector {
+	return VectorSelector{metric: name, matchers: matchers}
+}
+
+// Selector selects the series matching all the matchers, whatever their metric.
+func Selector(matchers ...Matcher) VectorSelector {
+	return VectorSelector{matchers: matchers}
+}
+
+// Where adds matchers to the selector.
+func (s VectorSelector) Where(matchers ...Matcher) VectorSelector {
+	s.matchers = append(append([]Matcher{}, s.matchers...), matchers...)
+	return s
+}
+
+// Offset shifts the evaluation time of the selector, e.g. `up offset 1h`.
+func (s VectorSelector) Offset(offset time.Duration) VectorSelector {
+	s.offset = offset
+	return s
+}
+
+// Range turns the selector into a range vector covering the given duration, e.g. `up[5m]`.
+func (s VectorSelector) Range(duration time.Duration) MatrixSelector {
+	return MatrixSelector{vector: s, rangeDuration: formatDuration(duration)}
+}
+
+// RangeVar turns the selector into a range vector covering the duration held by a variable, e.g. `up[$__rate_interval]`.
+// The name is given without the `$` prefix.
+func (s VectorSelector) RangeVar(variable string) MatrixSelector {
+	return MatrixSelector{vector: s, rangeDuration: "$" + variable}
+}
+
+func (s VectorSelector) String() string {
+	return s.selector() + formatOffset(s.offset)
+}
+
+// selector renders the selector without its offset.
+func (s VectorSelector) selector() string {
+	matchers := make([]string, 0, len(s.matchers)+1)
+	metric := s.metric
+	if metric != "" && !isLegacyName(metric, true) {
+		// a metric name that isn't a valid identifier must be quoted inside the braces
+		matchers = append(matchers, strconv.Quote(metric))
+		metric = ""
+	}
+	for _, m := range s.matchers {
+		matchers = append(matchers, m.String())
+	}
+	if metric != "" && len(matchers) == 0 {
+		return metric
+	}
+	return metric + "{" + strings.Join(matchers, ", ") + "}"
+}
+
+func (s VectorSelector) precedence() int {
+	return precedencePrimary
+}
+
+// MatrixSelector selects the series of a range vector, e.g. `http_requests_total[5m]`.
+// It is the argument of the functions over time, like Rate or AvgOverTime.
+type MatrixSelector struct {
+	vector        VectorSelector
+	rangeDuration string
+}
+
+// Offset shifts the evaluation time of the selector, e.g. `up[5m] offset 1h`.
+func (m MatrixSelector) Offset(offset time.Duration) MatrixSelector {
+	m.vector.offset = offset
+	return m
+}
+
+func (m MatrixSelector) String() string {
+	return m.vector.selector() + "[" + m.rangeDuration + "]" + formatOffset(m.vector.offset)
+}
+
+func (m MatrixSelector) precedence() int {
+	return precedencePrimary
+}
+
+func formatOffset(offset time.Duration) string {
+	if offset == 0 {
+		return ""
+	}
+	return " offset " + formatDuration(offset)
+}
@@ -0,0 +1,244 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// variableReference matches the references to a Perses variable: `$var`, `${var}` and `${var:format}`.
+var variableReference = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?:\.([^:}]+))?(?::([^}]+))?\}`)
+
+// builtinVariables are the variables always available in a Prometheus query, provided by Perses or by the plugin.
+var builtinVariables = map[string]bool{
+	"__dashboard":     true,
+	"__project":       true,
+	"__from":          true,
+	"__to":            true,
+	"__range":         true,
+	"__range_s":       true,
+	"__range_ms":      true,
+	"__interval":      true,
+	"__interval_ms":   true,
+	"__rate_interval": true,
+}
+
+// promqlParser accepts the experimental syntax, as it may be enabled on the Prometheus server running the queries.
+var promqlParser = parser.NewParser(parser.Options{
+	EnableExperimentalFunctions: true,
+	ExperimentalDurationExpr:    true,
+})
+
+// Error reports an invalid PromQL expression.
+type Error struct {
+	// Line and Column locate the error in the expression, starting at 1.
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid PromQL expression at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Validate parses the expression with the Prometheus parser.
+// The variable references are replaced beforehand by a placeholder fitting their position (a duration inside
+// brackets or after `offset`, a number after `@`, a name elsewhere). As the type of the variable values is unknown,
+// the errors located on a variable reference, like a name given where a number is expected, are ignored.
+// The returned error is an *Error.
+func Validate(expr string) error {
+	masked, placeholders := mask(expr)
+	_, err := promqlParser.ParseExpr(masked)
+	if err == nil {
+		return nil
+	}
+	var parseErrors parser.ParseErrors
+	if !errors.As(err, &parseErrors) {
+		return &Error{Line: 1, Column: 1, Message: err.Error()}
+	}
+	for _, parseErr := range parseErrors {
+		start, end := int(parseErr.PositionRange.Start), int(parseErr.PositionRange.End)
+		if overlapsAny(start, end, placeholders) {
+			continue
+		}
+		line, column := position(expr, start)
+		return &Error{Line: line, Column: column, Message: parseErr.Err.Error()}
+	}
+	return nil
+}
+
+// Variables returns the sorted names of the variables referenced by the expression, built-in variables included.
+func Variables(expr string) []string {
+	names := make(map[string]bool)
+	for _, ref := range references(expr) {
+		names[ref.name] = true
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// CheckVariables returns an error when the expression references a variable that is neither one of the defined
+// variables nor a built-in variable, like `$__rate_interval`.
+func CheckVariables(expr string, defined ...string) error {
+	definedSet := make(map[string]bool, len(defined))
+	for _, name := range defined {
+		definedSet[name] = true
+	}
+	var undefined []string
+	for _, name := range Variables(expr) {
+		if !definedSet[name] && !builtinVariables[name] {
+			undefined = append(undefined, name)
+		}
+	}
+	switch len(undefined) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("the variable %q is not defined", undefined[0])
+	}
+	return fmt.Errorf("the variables %q are not defined", undefined)
+}
+
+type reference struct {
+	name       string
+	start, end int
+}
+
+func references(expr string) []reference {
+	var result []reference
+	for _, match := range variableReference.FindAllStringSubmatchIndex(expr, -1) {
+		name := ""
+		if match[2] >= 0 {
+			name = expr[match[2]:match[3]]
+		} else {
+			name = expr[match[4]:match[5]]
+		}
+		if strings.Trim(name, "0123456789") == "" {
+			// `$1` is a capture group in the replacement of label_replace, not a variable
+			continue
+		}
+		result = append(result, reference{name: name, start: match[0], end: match[1]})
+	}
+	return result
+}
+
+type span struct {
+	start, end int
+}
+
+// mask replaces the variable references located outside the strings and the comments by a placeholder of the same
+// length, so the positions reported by the parser match the original expression.
+func mask(expr string) (string, []span) {
+	inCode, inBrackets := scan(expr)
+	masked := []byte(expr)
+	var placeholders []span
+	for _, ref := range references(expr) {
+		if !inCode[ref.start] {
+			continue
+		}
+		length := ref.end - ref.start
+		var placeholder string
+		switch previous := strings.TrimRight(expr[:ref.start], " \t\r\n"); {
+		case inBrackets[ref.start] || hasKeywordSuffix(previous, "offset"):
+			placeholder = "1s" + strings.Repeat(" ", length-2)
+		case strings.HasSuffix(previous, "@"):
+			placeholder = "1" + strings.Repeat(" ", length-1)
+		default:
+			placeholder = strings.Repeat("_", length)
+		}
+		copy(masked[ref.start:], placeholder)
+		placeholders = append(placeholders, span{start: ref.start, end: ref.end})
+	}
+	return string(masked), placeholders
+}
+
+// scan tells for every byte of the expression whether it is part of the code (neither a string nor a comment) and
+// whether it is inside brackets, i.e. a range or a subquery.
+func scan(expr string) (inCode []bool, inBrackets []bool) {
+	inCode = make([]bool, len(expr))
+	inBrackets = make([]bool, len(expr))
+	var quote byte
+	escaped, comment := false, false
+	depth := 0
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case comment:
+			comment = c != '\n'
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\' && quote != '`':
+				escaped = true
+			case c == quote:
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '#':
+			comment = true
+		default:
+			inCode[i] = true
+			if c == '[' {
+				depth++
+			} else if c == ']' && depth > 0 {
+				depth--
+			}
+			inBrackets[i] = depth > 0
+		}
+	}
+	return inCode, inBrackets
+}
+
+func hasKeywordSuffix(s string, keyword string) bool {
+	if len(s) < len(keyword) || !strings.EqualFold(s[len(s)-len(keyword):], keyword) {
+		return false
+	}
+	return len(s) == len(keyword) || !isLegacyName(s[len(s)-len(keyword)-1:len(s)-len(keyword)], true)
+}
+
+func overlapsAny(start int, end int, spans []span) bool {
+	for _, s := range spans {
+		if start < s.end && s.start < end || start == end && s.start <= start && start < s.end {
+			return true
+		}
+	}
+	return false
+}
+
+// position returns the line and the column of the byte at the given offset, starting at 1.
+func position(expr string, offset int) (int, int) {
+	if offset > len(expr) {
+		offset = len(expr)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if expr[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return line, offset - lineStart + 1
+}
@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, expr := range []string{
+		`sum by (job) (rate(http_requests_total{job="api"}[5m]))`,
+		`rate(http_requests_total{pod=~"$pod", env="${env:raw}"}[$__rate_interval])`,
+		`topk($count, sum by ($label) (${metric}_total))`,
+		`increase(up[$__range:$__interval]) offset $shift`,
+		`up @ $__to`,
+		`histogram_quantile($quantile, sum by (le) (rate(x_bucket[5m])))`,
+		`label_replace(up, "host", "$1", "instance", "(.*):.*") # host of $instance`,
+		`up{job="$job"} > $threshold`,
+	} {
+		if err := Validate(expr); err != nil {
+			t.Errorf("unexpected error for %s: %v", expr, err)
+		}
+	}
+}
+
+func TestValidateError(t *testing.T) {
+	testSuites := []struct {
+		expr     string
+		expected Error
+	}{
+		{
+			expr:     `sum(rate(up{job="$job"}[$__rate_interval])`,
+			expected: Error{Line: 1, Column: 43, Message: "unclosed left parenthesis"},
+		},
+		{
+			expr:     "rate(up[5m])\n  + on(job $x",
+			expected: Error{Line: 2, Column: 14, Message: "unclosed left parenthesis"},
+		},
+		{
+			expr:     `rate(up{job=~"$job"})`,
+			expected: Error{Line: 1, Column: 6, Message: "expected type range vector in call to function \"rate\", got instant vector"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.expr, func(t *testing.T) {
+			err := Validate(test.expr)
+			var validationErr *Error
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if *validationErr != test.expected {
+				t.Errorf("unexpected error:\n got: %+v\nwant: %+v", *validationErr, test.expected)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	expr := `sum by ($label) (rate(x{a="$a", b=~"${b:regex}", c="$1"}[$__rate_interval])) / ${a}`
+	if actual, expected := Variables(expr), []string{"__rate_interval", "a", "b", "label"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected variables: got %v, want %v", actual, expected)
+	}
+	if err := CheckVariables(expr, "a", "b", "label"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := CheckVariables(expr, "a"); err == nil || err.Error() != `the variables ["b" "label"] are not defined` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -16,6 +16,7 @@ package query
 import (
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/go-sdk/query"
+	"github.com/perses/plugins/prometheus/sdk/go/query/promql"
 	"github.com/perses/spec/go/common"
 	"github.com/perses/spec/go/plugin"
 )
@@ -48,11 +49,18 @@ func create(query string, options ...Option) (Builder, error) {
 		}
 	}
 
+	if builder.validate {
+		if err := promql.Validate(builder.Query); err != nil {
+			return *builder, err
+		}
+	}
+
 	return *builder, nil
 }
 
 type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
+	validate   bool
 }
 
 func PromQL(expr string, options ...Option) query.Option {
@@ -0,0 +1,109 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/perses/perses/go-sdk/dashboard"
+	"github.com/perses/plugins/prometheus/sdk/go/query/promql"
+	promqlVariable "github.com/perses/plugins/prometheus/sdk/go/variable/promql"
+)
+
+type pluginContent struct {
+	Kind string          `json:"kind"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// dashboardContent is the part of the dashboard read by CheckVariables.
+type dashboardContent struct {
+	Spec struct {
+		Variables []struct {
+			Spec struct {
+				Name   string        `json:"name"`
+				Plugin pluginContent `json:"plugin"`
+			} `json:"spec"`
+		} `json:"variables"`
+		Panels map[string]struct {
+			Spec struct {
+				Queries []struct {
+					Spec struct {
+						Plugin pluginContent `json:"plugin"`
+					} `json:"spec"`
+				} `json:"queries"`
+			} `json:"spec"`
+		} `json:"panels"`
+	} `json:"spec"`
+}
+
+// CheckVariables verifies that every variable referenced by the Prometheus queries and the PromQL variables of the
+// dashboard is defined. The built-in variables, like `$__rate_interval`, are always defined.
+// The variables defined outside the dashboard, i.e. the project and global variables, are given as external names.
+// It must be given as the last option of dashboard.New, once the variables and the panels have been added.
+func CheckVariables(external ...string) dashboard.Option {
+	return func(builder *dashboard.Builder) error {
+		data, err := json.Marshal(builder.Dashboard)
+		if err != nil {
+			return err
+		}
+		var content dashboardContent
+		if unmarshalErr := json.Unmarshal(data, &content); unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		defined := append([]string{}, external...)
+		for _, v := range content.Spec.Variables {
+			defined = append(defined, v.Spec.Name)
+		}
+		for _, v := range content.Spec.Variables {
+			if v.Spec.Plugin.Kind != promqlVariable.PluginKind {
+				continue
+			}
+			var spec struct {
+				Expr string `json:"expr"`
+			}
+			if unmarshalErr := json.Unmarshal(v.Spec.Plugin.Spec, &spec); unmarshalErr != nil {
+				return unmarshalErr
+			}
+			if checkErr := promql.CheckVariables(spec.Expr, defined...); checkErr != nil {
+				return fmt.Errorf("variable %q: %w", v.Spec.Name, checkErr)
+			}
+		}
+
+		panelIDs := make([]string, 0, len(content.Spec.Panels))
+		for id := range content.Spec.Panels {
+			panelIDs = append(panelIDs, id)
+		}
+		sort.Strings(panelIDs)
+		for _, id := range panelIDs {
+			for i, q := range content.Spec.Panels[id].Spec.Queries {
+				if q.Spec.Plugin.Kind != PluginKind {
+					continue
+				}
+				var spec struct {
+					Query string `json:"query"`
+				}
+				if unmarshalErr := json.Unmarshal(q.Spec.Plugin.Spec, &spec); unmarshalErr != nil {
+					return unmarshalErr
+				}
+				if checkErr := promql.CheckVariables(spec.Query, defined...); checkErr != nil {
+					return fmt.Errorf("panel %q, query %d: %w", id, i, checkErr)
+				}
+			}
+		}
+		return nil
+	}
+}
@@ -14,12 +14,10 @@
 package labelnames
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/perses/perses/go-sdk/datasource"
 	list_variable "github.com/perses/perses/go-sdk/variable/list-variable"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/plugins/prometheus/sdk/go/query/promql"
 )
 
 const PluginKind = "PrometheusLabelNamesVariable"
@@ -65,19 +63,38 @@ func PrometheusLabelNames(options ...Option) list_variable.Option {
 type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
 	Filters    []v1.Variable `json:"-" yaml:"-"`
+	// FilterMode is the operator of the matchers generated for the filtering variables, `=~` by default.
+	FilterMode promql.MatchType `json:"-" yaml:"-"`
+	// FilterMatchers replaces the matcher generated for a filtering variable, indexed by the name of the variable.
+	FilterMatchers map[string]promql.Matcher `json:"-" yaml:"-"`
 }
 
+// ApplyFilters adds to every series selector of the matchers a matcher per filtering variable, `name=~"$name"` by
+// default (see FilterMode and FilterMatcher). The selectors are parsed with the Prometheus parser, the generated
+// matchers are merged into their existing label matchers and skipped for the labels they already filter.
 func (b *Builder) ApplyFilters() error {
-	var filters []string
-	for _, variables := range b.Filters {
-		filters = append(filters, fmt.Sprintf("%s=~\"$%s\"", variables.Metadata.Name, variables.Metadata.Name))
+	var matchers []promql.Matcher
+	for _, variable := range b.Filters {
+		name := variable.Metadata.Name
+		matcher, ok := b.FilterMatchers[name]
+		if !ok {
+			matcher = promql.Matcher{Label: name, Type: b.FilterMode, Value: "$" + name}
+			if matcher.Type == "" {
+				matcher.Type = promql.MatchRegexp
+			}
+		}
+		matchers = append(matchers, matcher)
+	}
+	if len(matchers) == 0 {
+		return nil
 	}
 
-	for index, matcher := range b.Matchers {
-		// Add filter if matcher does not already have a metric filter
-		if !strings.Contains(matcher, "{") {
-			b.Matchers[index] = fmt.Sprintf("%s{%s}", matcher, strings.Join(filters, ","))
+	for index, selector := range b.Matchers {
+		merged, err := promql.MergeMatchers(selector, matchers...)
+		if err != nil {
+			return err
 		}
+		b.Matchers[index] = merged
 	}
 	return nil
 }
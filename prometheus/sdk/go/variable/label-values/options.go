@@ -16,6 +16,7 @@ package labelvalues
 import (
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	promDatasource "github.com/perses/plugins/prometheus/sdk/go/datasource"
+	"github.com/perses/plugins/prometheus/sdk/go/query/promql"
 )
 
 func LabelName(labelName string) Option {
@@ -52,3 +53,24 @@ func Filter(variables ...v1.Variable) Option {
 		return nil
 	}
 }
+
+// FilterMode defines the operator of the matchers generated for the filtering variables, e.g. promql.MatchEqual for
+// variables holding a single value. The default is promql.MatchRegexp, which supports the multi-value variables.
+func FilterMode(matchType promql.MatchType) Option {
+	return func(builder *Builder) error {
+		builder.FilterMode = matchType
+		return nil
+	}
+}
+
+// FilterMatcher replaces the matcher generated for the given filtering variable, to filter another label or to use a
+// specific format, e.g. promql.Eq("environment", "${env:raw}").
+func FilterMatcher(variable string, matcher promql.Matcher) Option {
+	return func(builder *Builder) error {
+		if builder.FilterMatchers == nil {
+			builder.FilterMatchers = make(map[string]promql.Matcher)
+		}
+		builder.FilterMatchers[variable] = matcher
+		return nil
+	}
+}
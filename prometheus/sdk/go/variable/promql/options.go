@@ -37,3 +37,12 @@ func Datasource(datasourceName string) Option {
 		return nil
 	}
 }
+
+// Validate enables the validation of the expression with the Prometheus parser when the variable is built.
+// The references to the other variables are accepted, see the Validate function of the query/promql package.
+func Validate() Option {
+	return func(builder *Builder) error {
+		builder.validate = true
+		return nil
+	}
+}
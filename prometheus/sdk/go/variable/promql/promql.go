@@ -16,6 +16,7 @@ package promql
 import (
 	"github.com/perses/perses/go-sdk/datasource"
 	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	promExpr "github.com/perses/plugins/prometheus/sdk/go/query/promql"
 )
 
 const PluginKind = "PrometheusPromQLVariable"
@@ -43,6 +44,12 @@ func create(expr string, options ...Option) (Builder, error) {
 		}
 	}
 
+	if builder.validate {
+		if err := promExpr.Validate(builder.Expr); err != nil {
+			return *builder, err
+		}
+	}
+
 	return *builder, nil
 }
 
@@ -60,4 +67,5 @@ func PrometheusPromQL(expr string, options ...Option) listvariable.Option {
 
 type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
+	validate   bool
 }
@@ -26,7 +26,7 @@ spec: close({
 	filters?: [...{
 		labelName:  string
 		labelValue: string
-		operator:   string
+		operator:   "=" | "!=" | "=~" | "!~"
 	}]
 	service?: string
 })
@@ -29,6 +29,8 @@ func MaxNodes(max int) Option {
 	}
 }
 
+// ProfileType defines the profile type to query, e.g. ProfileTypeCPU or any other ID of the form
+// `name:sample_type:sample_unit:period_type:period_unit`.
 func ProfileType(profileType string) Option {
 	return func(builder *Builder) error {
 		builder.ProfileType = profileType
@@ -43,6 +45,14 @@ func Filters(filters []LabelFilter) Option {
 	}
 }
 
+// AddFilter adds a filter on a label, e.g. AddFilter(Eq("env", "prod")).
+func AddFilter(filter LabelFilter) Option {
+	return func(builder *Builder) error {
+		builder.Filters = append(builder.Filters, filter)
+		return nil
+	}
+}
+
 func Service(service string) Option {
 	return func(builder *Builder) error {
 		builder.Service = &service
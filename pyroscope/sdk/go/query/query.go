@@ -14,6 +14,8 @@
 package query
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/go-sdk/query"
 	"github.com/perses/spec/go/plugin"
@@ -21,10 +23,71 @@ import (
 
 const PluginKind = "PyroscopeProfileQuery"
 
+// Well-known profile types, identified by `name:sample_type:sample_unit:period_type:period_unit`.
+const (
+	ProfileTypeCPU           = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+	ProfileTypeCPUSamples    = "process_cpu:samples:count:cpu:nanoseconds"
+	ProfileTypeAllocSpace    = "memory:alloc_space:bytes:space:bytes"
+	ProfileTypeAllocObjects  = "memory:alloc_objects:count:space:bytes"
+	ProfileTypeInuseSpace    = "memory:inuse_space:bytes:space:bytes"
+	ProfileTypeInuseObjects  = "memory:inuse_objects:count:space:bytes"
+	ProfileTypeGoroutines    = "goroutines:goroutine:count:goroutine:count"
+	ProfileTypeMutexCount    = "mutex:contentions:count:contentions:count"
+	ProfileTypeMutexDuration = "mutex:delay:nanoseconds:contentions:count"
+	ProfileTypeBlockCount    = "block:contentions:count:contentions:count"
+	ProfileTypeBlockDuration = "block:delay:nanoseconds:contentions:count"
+)
+
+type Operator string
+
+const (
+	OperatorEqual         Operator = "="
+	OperatorNotEqual      Operator = "!="
+	OperatorRegexMatch    Operator = "=~"
+	OperatorRegexNotMatch Operator = "!~"
+)
+
 type LabelFilter struct {
-	LabelName  *string `json:"labelName,omitempty" yaml:"labelName,omitempty"`
-	LabelValue *string `json:"labelValue,omitempty" yaml:"labelValue,omitempty"`
-	Operator   *string `json:"operator,omitempty" yaml:"operator,omitempty"`
+	LabelName  string   `json:"labelName" yaml:"labelName"`
+	LabelValue string   `json:"labelValue" yaml:"labelValue"`
+	Operator   Operator `json:"operator" yaml:"operator"`
+}
+
+// Eq filters the profiles having the label equal to the value, e.g. `service_name="api"`.
+func Eq(labelName string, labelValue string) LabelFilter {
+	return LabelFilter{LabelName: labelName, LabelValue: labelValue, Operator: OperatorEqual}
+}
+
+// Neq filters the profiles having the label not equal to the value, e.g. `env!="dev"`.
+func Neq(labelName string, labelValue string) LabelFilter {
+	return LabelFilter{LabelName: labelName, LabelValue: labelValue, Operator: OperatorNotEqual}
+}
+
+// Re filters the profiles having the label matching the regular expression, e.g. `pod=~"api-.*"`.
+func Re(labelName string, regexp string) LabelFilter {
+	return LabelFilter{LabelName: labelName, LabelValue: regexp, Operator: OperatorRegexMatch}
+}
+
+// NotRe filters the profiles having the label not matching the regular expression, e.g. `pod!~"test-.*"`.
+func NotRe(labelName string, regexp string) LabelFilter {
+	return LabelFilter{LabelName: labelName, LabelValue: regexp, Operator: OperatorRegexNotMatch}
+}
+
+// validate rejects the incomplete filters, that the Pyroscope query plugin would silently ignore.
+func (f LabelFilter) validate() error {
+	switch {
+	case f.LabelName == "":
+		return fmt.Errorf("the label name of the filter cannot be empty")
+	case f.LabelValue == "":
+		return fmt.Errorf("the value of the filter on the label %q cannot be empty", f.LabelName)
+	}
+	switch f.Operator {
+	case OperatorEqual, OperatorNotEqual, OperatorRegexMatch, OperatorRegexNotMatch:
+		return nil
+	case "":
+		return fmt.Errorf("the operator of the filter on the label %q cannot be empty", f.LabelName)
+	}
+	return fmt.Errorf("invalid operator %q for the filter on the label %q, the supported values are %q, %q, %q and %q", f.Operator, f.LabelName, OperatorEqual, OperatorNotEqual, OperatorRegexMatch, OperatorRegexNotMatch)
 }
 
 type PluginSpec struct {
@@ -48,6 +111,10 @@ func create(options ...Option) (Builder, error) {
 		}
 	}
 
+	if err := builder.validate(); err != nil {
+		return *builder, err
+	}
+
 	return *builder, nil
 }
 
@@ -55,6 +122,18 @@ type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
 }
 
+func (b *Builder) validate() error {
+	if b.ProfileType == "" {
+		return fmt.Errorf("the profile type is required")
+	}
+	for _, filter := range b.Filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ProfileQL(options ...Option) query.Option {
 	plg, err := create(options...)
 	return query.Option{
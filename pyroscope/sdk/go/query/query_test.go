@@ -0,0 +1,112 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title:    "profile type only",
+			options:  []Option{ProfileType(ProfileTypeCPU)},
+			expected: `{"profileType":"process_cpu:cpu:nanoseconds:cpu:nanoseconds"}`,
+		},
+		{
+			title: "filters with every operator",
+			options: []Option{
+				Datasource("pyroscope"),
+				ProfileType(ProfileTypeInuseSpace),
+				Service("api"),
+				MaxNodes(1024),
+				Filters([]LabelFilter{Eq("env", "prod"), Neq("region", "us-east-1")}),
+				AddFilter(Re("pod", "api-.*")),
+				AddFilter(NotRe("namespace", "test-.*")),
+			},
+			expected: `{"datasource":{"kind":"PyroscopeDatasource","name":"pyroscope"},"maxNodes":1024,"profileType":"memory:inuse_space:bytes:space:bytes","filters":[` +
+				`{"labelName":"env","labelValue":"prod","operator":"="},` +
+				`{"labelName":"region","labelValue":"us-east-1","operator":"!="},` +
+				`{"labelName":"pod","labelValue":"api-.*","operator":"=~"},` +
+				`{"labelName":"namespace","labelValue":"test-.*","operator":"!~"}` +
+				`],"service":"api"}`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("unexpected spec:\n got: %s\nwant: %s", data, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "no profile type",
+			options: []Option{Service("api")},
+			err:     "the profile type is required",
+		},
+		{
+			title:   "empty label name",
+			options: []Option{ProfileType(ProfileTypeCPU), AddFilter(Eq("", "prod"))},
+			err:     "the label name of the filter cannot be empty",
+		},
+		{
+			title:   "empty label value",
+			options: []Option{ProfileType(ProfileTypeCPU), AddFilter(Eq("env", ""))},
+			err:     `the value of the filter on the label "env" cannot be empty`,
+		},
+		{
+			title:   "empty operator",
+			options: []Option{ProfileType(ProfileTypeCPU), AddFilter(LabelFilter{LabelName: "env", LabelValue: "prod"})},
+			err:     `the operator of the filter on the label "env" cannot be empty`,
+		},
+		{
+			title:   "unknown operator",
+			options: []Option{ProfileType(ProfileTypeCPU), AddFilter(LabelFilter{LabelName: "env", LabelValue: "prod", Operator: "=="})},
+			err:     `invalid operator "==" for the filter on the label "env"`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatalf("expected the error %q, got nil", test.err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("unexpected error: got %q, want %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatter
+
+import "fmt"
+
+func SizeRange(min, max float64) Option { // nolint: revive
+	return func(builder *Builder) error {
+		if min >= max {
+			return fmt.Errorf("sizeRange min (%g) must be lower than max (%g)", min, max)
+		}
+		builder.SizeRange = []float64{min, max}
+		return nil
+	}
+}
+
+func Link(url string) Option {
+	return func(builder *Builder) error {
+		builder.Link = url
+		return nil
+	}
+}
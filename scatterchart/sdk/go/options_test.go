@@ -0,0 +1,114 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSizeRange(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		min       float64
+		max       float64
+		expected  []float64
+		expectErr bool
+	}{
+		{title: "valid range", min: 4, max: 12, expected: []float64{4, 12}},
+		{title: "decimal values", min: 0.5, max: 1.5, expected: []float64{0.5, 1.5}},
+		{title: "equal bounds", min: 8, max: 8, expectErr: true},
+		{title: "inverted bounds", min: 12, max: 4, expectErr: true},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(SizeRange(test.min, test.max))
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(builder.SizeRange, test.expected) {
+				t.Errorf("unexpected size range: got %v, want %v", builder.SizeRange, test.expected)
+			}
+		})
+	}
+}
+
+func TestPluginSpecUnmarshal(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		data      string
+		expectErr bool
+	}{
+		{title: "no size range", data: `{"link":"/trace"}`},
+		{title: "valid size range", data: `{"sizeRange":[4,12]}`},
+		{title: "single value", data: `{"sizeRange":[4]}`, expectErr: true},
+		{title: "three values", data: `{"sizeRange":[4,8,12]}`, expectErr: true},
+		{title: "inverted bounds", data: `{"sizeRange":[12,4]}`, expectErr: true},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var spec PluginSpec
+			err := json.Unmarshal([]byte(test.data), &spec)
+			if test.expectErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestSchemaFixtures checks that the specs of the schema fixtures are decoded by the SDK.
+func TestSchemaFixtures(t *testing.T) {
+	testSuites := []struct {
+		file     string
+		expected PluginSpec
+	}{
+		{
+			file:     "scatter.json",
+			expected: PluginSpec{Link: "/datasource/${datasourceName}/trace/${traceId}"},
+		},
+		{
+			file:     "scatter-size-range.json",
+			expected: PluginSpec{SizeRange: []float64{4, 12}, Link: "/datasource/${datasourceName}/trace/${traceId}"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("..", "..", "schemas", "tests", "valid", test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixture struct {
+				Spec PluginSpec `json:"spec"`
+			}
+			if err := json.Unmarshal(data, &fixture); err != nil {
+				t.Fatalf("unable to decode the spec: %v", err)
+			}
+			if !reflect.DeepEqual(fixture.Spec, test.expected) {
+				t.Errorf("unexpected spec:\n got: %+v\nwant: %+v", fixture.Spec, test.expected)
+			}
+		})
+	}
+}
@@ -13,13 +13,88 @@
 
 package scatter
 
-import "github.com/perses/perses/go-sdk/panel"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/perses/perses/go-sdk/panel"
+)
 
 const PluginKind = "ScatterChart"
 
-func Chart() panel.Option {
+type PluginSpec struct {
+	// SizeRange is the [min, max] range of the points size.
+	SizeRange []float64 `json:"sizeRange,omitempty" yaml:"sizeRange,omitempty"`
+	Link      string    `json:"link,omitempty" yaml:"link,omitempty"`
+}
+
+func (s *PluginSpec) UnmarshalJSON(data []byte) error {
+	type plain PluginSpec
+	var tmp PluginSpec
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PluginSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var tmp PluginSpec
+	type plain PluginSpec
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PluginSpec) validate() error {
+	if s.SizeRange == nil {
+		return nil
+	}
+	if len(s.SizeRange) != 2 {
+		return fmt.Errorf("sizeRange must contain exactly two values, got %d", len(s.SizeRange))
+	}
+	if s.SizeRange[0] >= s.SizeRange[1] {
+		return fmt.Errorf("sizeRange min (%g) must be lower than max (%g)", s.SizeRange[0], s.SizeRange[1])
+	}
+	return nil
+}
+
+type Option func(plugin *Builder) error
+
+type Builder struct {
+	PluginSpec `json:",inline" yaml:",inline"`
+}
+
+func create(options ...Option) (Builder, error) {
+	builder := &Builder{
+		PluginSpec: PluginSpec{},
+	}
+
+	for _, opt := range options {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+
+	return *builder, nil
+}
+
+func Chart(options ...Option) panel.Option {
 	return func(builder *panel.Builder) error {
+		r, err := create(options...)
+		if err != nil {
+			return err
+		}
 		builder.Spec.Plugin.Kind = PluginKind
+		builder.Spec.Plugin.Spec = r.PluginSpec
 		return nil
 	}
 }
@@ -0,0 +1,60 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// knownDrifts lists the drifts accepted until the Go SDK or the schema is fixed, indexed by workspace.
+// Each drift is associated with the reason why it is accepted.
+var knownDrifts = map[string]map[string]string{
+	"barchart": {
+		"BarChart: spec.visual: allowed by the schema but cannot be emitted by the Go SDK": "not supported by the Go SDK yet, to be added by a separate request",
+	},
+	"flamechart": {
+		"FlameChart: spec.traceHeight: allowed by the schema but cannot be emitted by the Go SDK": "not supported by the Go SDK yet, to be added by a separate request",
+	},
+	"piechart": {
+		"PieChart: spec.colorPalette: allowed by the schema but cannot be emitted by the Go SDK": "not supported by the Go SDK yet, to be added by a separate request",
+		"PieChart: spec.querySettings: emitted by the Go SDK but not allowed by the schema":      "WithQuerySettings is deprecated and kept for backward compatibility",
+		"PieChart: spec.showLabels: allowed by the schema but cannot be emitted by the Go SDK":   "not supported by the Go SDK yet, to be added by a separate request",
+		"PieChart: spec.visual: emitted by the Go SDK but not allowed by the schema":             "WithVisual is deprecated and kept for backward compatibility",
+	},
+}
+
+// filterKnownDrifts removes the known drifts of the workspace from the given drifts,
+// and adds a drift for every known drift that is not found anymore.
+func filterKnownDrifts(workspace string, drifts []string) []string {
+	known := knownDrifts[filepath.Base(workspace)]
+	found := make(map[string]bool, len(known))
+	var result []string
+	for _, d := range drifts {
+		if _, ok := known[d]; ok {
+			found[d] = true
+			continue
+		}
+		result = append(result, d)
+	}
+	var stale []string
+	for d := range known {
+		if !found[d] {
+			stale = append(stale, fmt.Sprintf("known drift %q is not found anymore, it must be removed from the known drifts", d))
+		}
+	}
+	sort.Strings(stale)
+	return append(result, stale...)
+}
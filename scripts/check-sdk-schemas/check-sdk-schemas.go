@@ -0,0 +1,114 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/sirupsen/logrus"
+)
+
+type result struct {
+	workspace string
+	drifts    []string
+}
+
+// checkWorkspace compares the plugin specs of the Go SDK of the given workspace with the associated CUE schemas.
+// It returns one line per drift, prefixed by the plugin kind.
+func checkWorkspace(workspace string) ([]string, error) {
+	sdkPath := filepath.Join(workspace, "sdk", "go")
+	if _, err := os.Stat(sdkPath); os.IsNotExist(err) {
+		logrus.Debugf("plugin %s doesn't have a Go SDK", workspace)
+		return nil, nil
+	}
+	goSpecs, err := loadGoSpecs(workspace)
+	if err != nil {
+		return nil, err
+	}
+	schemaSpecs, err := loadSchemaSpecs(filepath.Join(workspace, "schemas"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the schemas: %w", err)
+	}
+	kinds := make([]string, 0, len(goSpecs))
+	for kind := range goSpecs {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var drifts []string
+	for _, kind := range kinds {
+		schema, ok := schemaSpecs[kind]
+		if !ok {
+			logrus.Warnf("plugin %s: no schema found for the kind %s declared in the Go SDK", workspace, kind)
+			continue
+		}
+		for _, d := range compare("spec", goSpecs[kind], schema) {
+			drifts = append(drifts, fmt.Sprintf("%s: %s", kind, d))
+		}
+	}
+	return filterKnownDrifts(workspace, drifts), nil
+}
+
+// This script checks that the Go SDK of every plugin can emit exactly what the CUE schemas accept.
+// For each plugin spec declared in the SDK, it reports:
+// - the fields emitted by the Go SDK that are not allowed by the schema
+// - the fields allowed by the schema that cannot be emitted by the Go SDK
+// - the fields whose type differs between the Go SDK and the schema
+//
+// The JSON emitted by the Go SDK is produced by a program generated in each plugin module, which marshals a sample of
+// every plugin spec where all the fields are set. The Go dependencies of the plugin modules must be available and the
+// CUE dependencies of the schemas must be available in the CUE cache (e.g. after running `percli plugin test-schemas`).
+//
+// The drifts that are accepted for now are listed in knownDrifts. A known drift that is not found anymore is reported,
+// so that the list doesn't outlive the drifts.
+//
+// Usage:
+//
+//	go run ./scripts/check-sdk-schemas
+func main() {
+	workspaces := npm.MustGetWorkspaces(".")
+	plugins := make([]async.Future[result], 0, len(workspaces))
+
+	for _, workspace := range workspaces {
+		logrus.Infof("Checking Go SDK of plugin %s against its schemas", workspace)
+		plugins = append(plugins, async.Async(func() (result, error) {
+			drifts, err := checkWorkspace(workspace)
+			return result{workspace: workspace, drifts: drifts}, err
+		}))
+	}
+	isErr := false
+	for _, plugin := range plugins {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		res, checkErr := plugin.AwaitWithContext(ctx)
+		cancel()
+		if checkErr != nil {
+			isErr = true
+			logrus.WithError(checkErr).Errorf("failed to check the Go SDK of plugin %s", res.workspace)
+			continue
+		}
+		for _, d := range res.drifts {
+			isErr = true
+			logrus.Errorf("plugin %s: %s", res.workspace, d)
+		}
+	}
+	if isErr {
+		logrus.Fatal("some Go SDKs drifted from their schemas")
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileSchemaSpec(t *testing.T, src string) cue.Value {
+	value := cuecontext.New().CompileString(src)
+	require.NoError(t, value.Err())
+	return value.LookupPath(cue.ParsePath("spec"))
+}
+
+func jsonShape(t *testing.T, src string) *shape {
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(src), &value))
+	return shapeOf(value)
+}
+
+func driftStrings(drifts []drift) []string {
+	result := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		result = append(result, d.String())
+	}
+	return result
+}
+
+func TestFindSpecTypes(t *testing.T) {
+	src := `package panel
+const (
+	PluginKind             = "AlertTable"
+	SilenceTablePluginKind = "SilenceTable"
+	orphanPluginKind       = "Orphan"
+)
+type PluginSpec struct{}
+type SilenceTablePluginSpec struct{}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "panel.go", src, parser.SkipObjectResolution)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"AlertTable":   "PluginSpec",
+		"SilenceTable": "SilenceTablePluginSpec",
+	}, findSpecTypes([]*ast.File{file}))
+}
+
+func TestCompare(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		goJSON   string
+		cueSrc   string
+		expected []string
+	}{
+		{
+			title:  "matching spec",
+			goJSON: `{"datasource": {"kind": "x", "name": "x"}, "query": "x", "earliest_time": "x"}`,
+			cueSrc: `spec: close({
+	datasource?: {kind: "SplunkDatasource", name?: string}
+	query: string
+	earliest_time?: string
+})`,
+			expected: []string{},
+		},
+		{
+			title:  "field missing on both sides",
+			goJSON: `{"query": "x", "timeField": "x"}`,
+			cueSrc: `spec: close({
+	query: string
+	earliest_time?: string
+	latest_time?: string
+})`,
+			expected: []string{
+				"spec.earliest_time: allowed by the schema but cannot be emitted by the Go SDK",
+				"spec.latest_time: allowed by the schema but cannot be emitted by the Go SDK",
+				"spec.timeField: emitted by the Go SDK but not allowed by the schema",
+			},
+		},
+		{
+			title:  "type mismatch in nested struct and list",
+			goJSON: `{"legend": {"position": "x", "size": 1}, "values": [true]}`,
+			cueSrc: `spec: close({
+	legend?: close({
+		position: "bottom" | "right"
+		size?: "small" | "medium"
+	})
+	values?: [...string]
+})`,
+			expected: []string{
+				"spec.legend.size: the Go SDK emits a number but the schema expects string",
+				"spec.values[*]: the Go SDK emits a bool but the schema expects string",
+			},
+		},
+		{
+			title:  "custom marshaller and unknown type",
+			goJSON: `{"width": "auto", "plugin": null}`,
+			cueSrc: `spec: close({
+	width?: "auto" | number
+	plugin?: {kind: string, spec: {...}}
+})`,
+			expected: []string{},
+		},
+		{
+			title:  "open struct and map",
+			goJSON: `{"labels": {"key": "x"}, "unknown": "x"}`,
+			cueSrc: `spec: {
+	labels: [string]: string
+	...
+}`,
+			expected: []string{},
+		},
+		{
+			title:  "list of definition disjuncts",
+			goJSON: `{"actionsList": [{"type": "x", "name": "x", "eventName": "x", "url": "x"}]}`,
+			cueSrc: `#eventAction: close({type: "event", name: string, eventName: string})
+#webhookAction: close({type: "webhook", name: string, url: string})
+#itemAction: #eventAction | #webhookAction
+spec: close({
+	actionsList: [...#itemAction]
+})`,
+			expected: []string{},
+		},
+		{
+			title:  "embedded definition disjunction",
+			goJSON: `{"directUrl": "x", "proxy": {"kind": "x", "spec": {"url": "x"}}}`,
+			cueSrc: `#proxy: {kind: "HTTPProxy", spec: {url: string}}
+#baseHTTPDatasourceSpec: {directUrl: string} | {proxy: #proxy}
+spec: {
+	#baseHTTPDatasourceSpec
+}`,
+			expected: []string{},
+		},
+		{
+			title:  "drifts in the variants of a disjunction",
+			goJSON: `{"kind": "x", "spec": {"value": 1}, "color": "x"}`,
+			cueSrc: `#value: close({kind: "Value", spec: {value: string}})
+#range: close({kind: "Range", spec: {from?: number, to?: number}})
+spec: #value | #range`,
+			expected: []string{
+				"spec.spec.value: the Go SDK emits a number but the schema expects string",
+				"spec.color: emitted by the Go SDK but not allowed by the schema",
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			schema := compileSchemaSpec(t, test.cueSrc)
+			assert.Equal(t, test.expected, driftStrings(compare("spec", jsonShape(t, test.goJSON), schema)))
+		})
+	}
+}
+
+func TestFilterKnownDrifts(t *testing.T) {
+	knownDrifts["fake"] = map[string]string{
+		"Fake: spec.old: emitted by the Go SDK but not allowed by the schema":     "deprecated",
+		"Fake: spec.removed: emitted by the Go SDK but not allowed by the schema": "deprecated",
+	}
+	defer delete(knownDrifts, "fake")
+	drifts := []string{
+		"Fake: spec.old: emitted by the Go SDK but not allowed by the schema",
+		"Fake: spec.new: allowed by the schema but cannot be emitted by the Go SDK",
+	}
+	assert.Equal(t, []string{
+		"Fake: spec.new: allowed by the schema but cannot be emitted by the Go SDK",
+		`known drift "Fake: spec.removed: emitted by the Go SDK but not allowed by the schema" is not found anymore, it must be removed from the known drifts`,
+	}, filterKnownDrifts("plugins/fake", drifts))
+}
+
+func TestLoadGoSpecs(t *testing.T) {
+	workspace := t.TempDir()
+	sdkPath := filepath.Join(workspace, "sdk", "go", "query")
+	require.NoError(t, os.MkdirAll(sdkPath, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "go.mod"), []byte("module example.com/fake\n\ngo 1.24\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(sdkPath, "query.go"), []byte(`package query
+
+const PluginKind = "FakeQuery"
+
+type Size struct {
+	Auto bool
+}
+
+func (s Size) MarshalJSON() ([]byte, error) {
+	if s.Auto {
+		return []byte("\"auto\""), nil
+	}
+	return []byte("0"), nil
+}
+
+type PluginSpec struct {
+	Query   string            `+"`json:\"query\"`"+`
+	Limit   int               `+"`json:\"limit,omitempty\"`"+`
+	Width   *Size             `+"`json:\"width,omitempty\"`"+`
+	Headers map[string]string `+"`json:\"headers,omitempty\"`"+`
+	Plugin  any               `+"`json:\"plugin,omitempty\"`"+`
+}
+`), 0600))
+
+	goSpecs, err := loadGoSpecs(workspace)
+	require.NoError(t, err)
+	require.Contains(t, goSpecs, "FakeQuery")
+	schema := compileSchemaSpec(t, `spec: close({
+	query: string
+	limit?: int
+	width?: "auto" | number
+	headers?: [string]: string
+	plugin?: _
+	step?: string
+})`)
+	assert.Equal(t, []string{
+		"spec.step: allowed by the schema but cannot be emitted by the Go SDK",
+	}, driftStrings(compare("spec", goSpecs["FakeQuery"], schema)))
+	entries, err := os.ReadDir(workspace)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "the generated program must be removed")
+}
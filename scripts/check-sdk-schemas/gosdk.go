@@ -0,0 +1,326 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	pluginKindSuffix = "PluginKind"
+	pluginSpecSuffix = "PluginSpec"
+	programDirPrefix = "_check-sdk-schemas-"
+)
+
+// sampleSource is the source of the sample package, copied in the generated program.
+//
+//go:embed sample/sample.go
+var sampleSource string
+
+var programTemplate = template.Must(template.New("main.go").Parse(`// Code generated by check-sdk-schemas. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+{{ range $i, $pkg := . }}
+	p{{ $i }} {{ printf "%q" $pkg.ImportPath }}
+{{- end }}
+)
+
+func main() {
+	specs := map[string]reflect.Type{
+{{- range $i, $pkg := . }}
+{{- range $kind, $type := $pkg.SpecTypes }}
+		{{ printf "%q" $kind }}: reflect.TypeFor[p{{ $i }}.{{ $type }}](),
+{{- end }}
+{{- end }}
+	}
+	result := make(map[string]json.RawMessage, len(specs))
+	for kind, t := range specs {
+		data, err := json.Marshal(Spec(t))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to marshal the sample spec of %s: %s\n", kind, err)
+			os.Exit(1)
+		}
+		result[kind] = data
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+type shapeKind int
+
+const (
+	anyShape shapeKind = iota
+	stringShape
+	numberShape
+	boolShape
+	listShape
+	structShape
+)
+
+func (k shapeKind) String() string {
+	switch k {
+	case stringShape:
+		return "string"
+	case numberShape:
+		return "number"
+	case boolShape:
+		return "bool"
+	case listShape:
+		return "list"
+	case structShape:
+		return "struct"
+	default:
+		return "any"
+	}
+}
+
+// shape is the structure of the JSON emitted by the Go SDK for a plugin spec.
+// It is computed from a sample of the spec where every field is set (see the sample package), so it describes every
+// field the builder can emit and not only the ones set by default.
+type shape struct {
+	kind   shapeKind
+	fields map[string]*shape
+	elem   *shape
+}
+
+// shapeOf returns the shape of a value decoded from JSON.
+// Maps emitted by the Go SDK appear as structs holding the single key sample.MapKey.
+func shapeOf(value any) *shape {
+	switch v := value.(type) {
+	case string:
+		return &shape{kind: stringShape}
+	case float64:
+		return &shape{kind: numberShape}
+	case bool:
+		return &shape{kind: boolShape}
+	case []any:
+		if len(v) == 0 {
+			return &shape{kind: listShape, elem: &shape{kind: anyShape}}
+		}
+		return &shape{kind: listShape, elem: shapeOf(v[0])}
+	case map[string]any:
+		result := &shape{kind: structShape, fields: make(map[string]*shape, len(v))}
+		for name, field := range v {
+			result.fields[name] = shapeOf(field)
+		}
+		return result
+	default:
+		// null is emitted by the fields whose type is unknown (interfaces).
+		return &shape{kind: anyShape}
+	}
+}
+
+// sdkPackage is a package of a Go SDK declaring plugin specs.
+type sdkPackage struct {
+	ImportPath string
+	// SpecTypes are the names of the plugin spec types, indexed by plugin kind.
+	SpecTypes map[string]string
+}
+
+// findSpecTypes returns the name of every plugin spec type declared in the given files, indexed by plugin kind.
+// A plugin spec is matched with its kind through their common prefix, e.g. `PluginKind` with `PluginSpec`
+// or `SilenceTablePluginKind` with `SilenceTablePluginSpec`.
+func findSpecTypes(files []*ast.File) map[string]string {
+	kinds := make(map[string]string)
+	types := make(map[string]bool)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					types[s.Name.Name] = s.Name.IsExported()
+				case *ast.ValueSpec:
+					if genDecl.Tok != token.CONST {
+						continue
+					}
+					for i, name := range s.Names {
+						if !strings.HasSuffix(name.Name, pluginKindSuffix) || i >= len(s.Values) {
+							continue
+						}
+						lit, isLit := s.Values[i].(*ast.BasicLit)
+						if !isLit || lit.Kind != token.STRING {
+							continue
+						}
+						if value, err := strconv.Unquote(lit.Value); err == nil {
+							kinds[name.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+	result := make(map[string]string)
+	for constName, kind := range kinds {
+		typeName := strings.TrimSuffix(constName, pluginKindSuffix) + pluginSpecSuffix
+		if types[typeName] {
+			result[kind] = typeName
+		}
+	}
+	return result
+}
+
+// findSDKPackages returns every package located under the `sdk/go` directory of the workspace that declares plugin specs.
+func findSDKPackages(workspace string) ([]sdkPackage, error) {
+	modulePath, err := readModulePath(filepath.Join(workspace, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	var result []sdkPackage
+	err = filepath.WalkDir(filepath.Join(workspace, "sdk", "go"), func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		files, parseErr := parseGoFiles(currentPath)
+		if parseErr != nil {
+			return parseErr
+		}
+		specTypes := findSpecTypes(files)
+		if len(specTypes) == 0 {
+			return nil
+		}
+		rel, relErr := filepath.Rel(workspace, currentPath)
+		if relErr != nil {
+			return relErr
+		}
+		result = append(result, sdkPackage{ImportPath: path.Join(modulePath, filepath.ToSlash(rel)), SpecTypes: specTypes})
+		return nil
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].ImportPath < result[j].ImportPath })
+	return result, err
+}
+
+func readModulePath(goModPath string) (string, error) {
+	data, err := os.ReadFile(goModPath) //nolint: gosec
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modulePath), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module path found in %s", goModPath)
+}
+
+func parseGoFiles(dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, parseErr := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// loadGoSpecs returns the shape of the JSON emitted by every plugin spec of the Go SDK of the workspace, indexed by
+// plugin kind.
+// The specs are marshalled by a program generated in a temporary directory of the workspace and run with `go run`, so
+// they are encoded by the Go SDK itself, custom marshallers included.
+func loadGoSpecs(workspace string) (map[string]*shape, error) {
+	packages, err := findSDKPackages(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the Go SDK: %w", err)
+	}
+	if len(packages) == 0 {
+		return map[string]*shape{}, nil
+	}
+	output, err := runProgram(workspace, packages)
+	if err != nil {
+		return nil, err
+	}
+	var samples map[string]any
+	if unmarshalErr := json.Unmarshal(output, &samples); unmarshalErr != nil {
+		return nil, fmt.Errorf("unable to decode the sample specs: %w", unmarshalErr)
+	}
+	result := make(map[string]*shape, len(samples))
+	for kind, sample := range samples {
+		result[kind] = shapeOf(sample)
+	}
+	return result, nil
+}
+
+func runProgram(workspace string, packages []sdkPackage) ([]byte, error) {
+	var program bytes.Buffer
+	if err := programTemplate.Execute(&program, packages); err != nil {
+		return nil, fmt.Errorf("unable to generate the program marshalling the specs: %w", err)
+	}
+	dir, err := os.MkdirTemp(workspace, programDirPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if writeErr := os.WriteFile(filepath.Join(dir, "main.go"), program.Bytes(), 0600); writeErr != nil {
+		return nil, writeErr
+	}
+	sample := strings.Replace(sampleSource, "\npackage sample\n", "\npackage main\n", 1)
+	if writeErr := os.WriteFile(filepath.Join(dir, "sample.go"), []byte(sample), 0600); writeErr != nil {
+		return nil, writeErr
+	}
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Dir = workspace
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("unable to marshal the specs of the Go SDK: %w\nOutput: %s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("unable to marshal the specs of the Go SDK: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,110 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sample builds a sample of a Go type where every field is set, so that its JSON encoding contains every field
+// the type can emit, including the ones tagged with `omitempty`.
+//
+// The source of this package is copied in the program generated in each plugin module to marshal the plugin specs, so it
+// must only depend on the standard library.
+package sample
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// MapKey is the key of the single entry put in every map.
+const MapKey = "key"
+
+// maxExpansion is the number of times a recursive type is expanded.
+const maxExpansion = 2
+
+var rawMessageType = reflect.TypeFor[json.RawMessage]()
+
+// Spec returns a value of the given type where every exported field is set to a non-zero value:
+// `true` for booleans, `1` for numbers, `"x"` for strings, a single element for slices and maps.
+// Interfaces are set to a JSON `null` as their concrete type is unknown, and recursive types are expanded twice so that
+// the recursive fields appear in the sample.
+func Spec(t reflect.Type) any {
+	v := reflect.New(t).Elem()
+	fill(v, make(map[reflect.Type]int))
+	return v.Interface()
+}
+
+func fill(v reflect.Value, visiting map[reflect.Type]int) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Interface:
+		if rawMessageType.AssignableTo(v.Type()) {
+			v.Set(reflect.ValueOf(json.RawMessage("null")))
+		}
+	case reflect.Pointer:
+		if isExpanded(v.Type().Elem(), visiting) {
+			return
+		}
+		elem := reflect.New(v.Type().Elem())
+		fill(elem.Elem(), visiting)
+		v.Set(elem)
+	case reflect.Slice:
+		if isExpanded(v.Type().Elem(), visiting) {
+			return
+		}
+		slice := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(slice.Index(0), visiting)
+		v.Set(slice)
+	case reflect.Array:
+		for i := range v.Len() {
+			fill(v.Index(i), visiting)
+		}
+	case reflect.Map:
+		if isExpanded(v.Type().Elem(), visiting) {
+			return
+		}
+		key := reflect.New(v.Type().Key()).Elem()
+		if key.Kind() == reflect.String {
+			key.SetString(MapKey)
+		} else {
+			fill(key, visiting)
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, visiting)
+		m := reflect.MakeMapWithSize(v.Type(), 1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Struct:
+		visiting[v.Type()]++
+		defer func() { visiting[v.Type()]-- }()
+		for i := range v.NumField() {
+			if field := v.Field(i); field.CanSet() {
+				fill(field, visiting)
+			}
+		}
+	}
+}
+
+// isExpanded tells whether the values of the given type must be left empty to stop expanding a recursive type.
+func isExpanded(t reflect.Type, visiting map[reflect.Type]int) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return visiting[t] >= maxExpansion
+}
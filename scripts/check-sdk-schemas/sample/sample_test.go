@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sample
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type size struct {
+	Auto bool
+}
+
+func (s size) MarshalJSON() ([]byte, error) {
+	if s.Auto {
+		return []byte(`"auto"`), nil
+	}
+	return []byte(`0`), nil
+}
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type legend struct {
+	Position string `json:"position"`
+}
+
+type Inline struct {
+	Inline bool `json:"inline,omitempty"`
+}
+
+type spec struct {
+	Inline  `json:",inline"`
+	Query   string            `json:"query"`
+	Limit   *int              `json:"limit,omitempty"`
+	Ratio   float64           `json:"ratio,omitempty"`
+	Legend  *legend           `json:"legend,omitempty"`
+	Values  []string          `json:"values,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Width   *size             `json:"width,omitempty"`
+	Spec    any               `json:"spec,omitempty"`
+	Tree    node              `json:"tree"`
+	Ignored string            `json:"-"`
+	private string
+}
+
+func TestSpec(t *testing.T) {
+	data, err := json.Marshal(Spec(reflect.TypeFor[spec]()))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"inline": true,
+		"query": "x",
+		"limit": 1,
+		"ratio": 1,
+		"legend": {"position": "x"},
+		"values": ["x"],
+		"headers": {"key": "x"},
+		"width": "auto",
+		"spec": null,
+		"tree": {"name": "x", "children": [{"name": "x"}]}
+	}`, string(data))
+}
@@ -0,0 +1,268 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+)
+
+// drift is a difference between the JSON emitted by a Go SDK and the CUE schema of the same plugin.
+type drift struct {
+	path   string
+	reason string
+}
+
+func (d drift) String() string {
+	return fmt.Sprintf("%s: %s", d.path, d.reason)
+}
+
+// loadSchemaSpecs loads every model schema located under schemasPath and returns their `spec` value, indexed by plugin kind.
+// Migration schemas are ignored.
+func loadSchemaSpecs(schemasPath string) (map[string]cue.Value, error) {
+	ctx := cuecontext.New()
+	result := make(map[string]cue.Value)
+	err := filepath.WalkDir(schemasPath, func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "migrate" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(currentPath) != ".cue" {
+			return nil
+		}
+		data, readErr := os.ReadFile(currentPath) //nolint: gosec
+		if readErr != nil {
+			return readErr
+		}
+		if !strings.Contains(string(data), "package model") {
+			return nil
+		}
+		currentDir := filepath.Dir(currentPath)
+		kind, spec, schemaErr := loadSchemaSpec(ctx, currentDir)
+		if schemaErr != nil {
+			return schemaErr
+		}
+		result[kind] = spec
+		// every file of the directory is part of the same package, no need to look at the other ones.
+		return fs.SkipDir
+	})
+	return result, err
+}
+
+func loadSchemaSpec(ctx *cue.Context, schemaPath string) (string, cue.Value, error) {
+	buildInstances := load.Instances([]string{}, &load.Config{Dir: schemaPath, Package: "model"})
+	if len(buildInstances) != 1 {
+		return "", cue.Value{}, fmt.Errorf("the number of build instances in %s is != 1", schemaPath)
+	}
+	if buildInstances[0].Err != nil {
+		return "", cue.Value{}, fmt.Errorf("failed to load schema from %q: %w", schemaPath, buildInstances[0].Err)
+	}
+	schema := ctx.BuildInstance(buildInstances[0])
+	if schema.Err() != nil {
+		return "", cue.Value{}, schema.Err()
+	}
+	kind, err := schema.LookupPath(cue.ParsePath("kind")).String()
+	if err != nil {
+		return "", cue.Value{}, fmt.Errorf("invalid schema at %s: unable to read the `kind` field: %w", schemaPath, err)
+	}
+	spec := schema.LookupPath(cue.ParsePath("spec"))
+	if !spec.Exists() {
+		return "", cue.Value{}, fmt.Errorf("invalid schema at %s: required `spec` field is missing", schemaPath)
+	}
+	return kind, spec, nil
+}
+
+// compare walks through the shape of a Go spec and the matching CUE schema at the same time
+// and returns every field that exists only on one side or whose type differs.
+func compare(path string, goShape *shape, schema cue.Value) []drift {
+	if goShape.kind == anyShape {
+		return nil
+	}
+	schemaKind := schema.IncompleteKind()
+	if schemaKind == cue.TopKind {
+		return nil
+	}
+	if schemaKind&expectedKind(goShape.kind) == 0 {
+		return []drift{{path: path, reason: fmt.Sprintf("the Go SDK emits a %s but the schema expects %s", goShape.kind, schemaKind)}}
+	}
+	switch goShape.kind {
+	case structShape:
+		return compareStruct(path, goShape, schema)
+	case listShape:
+		return compareElem(path+"[*]", goShape.elem, schema.LookupPath(cue.MakePath(cue.AnyIndex)))
+	default:
+		return nil
+	}
+}
+
+func compareElem(path string, goShape *shape, schema cue.Value) []drift {
+	if !schema.Exists() {
+		return nil
+	}
+	return compare(path, goShape, schema)
+}
+
+// structVariant is one of the structs accepted by a schema.
+type structVariant struct {
+	value  cue.Value
+	fields map[string]cue.Value
+}
+
+// compareStruct compares a Go struct with a struct schema, or with a disjunction of structs such as `#a | #b`.
+// A Go struct has to cover every variant of a disjunction, as it is usually the union of all the variants
+// (e.g. an action holding the fields of both the event and the webhook actions):
+// - a field of any variant that cannot be emitted by the Go SDK is reported,
+// - a field emitted by the Go SDK is reported when no variant allows it, or when its type matches none of them.
+func compareStruct(path string, goShape *shape, schema cue.Value) []drift {
+	variants, names, err := structVariants(schema)
+	if err != nil {
+		return []drift{{path: path, reason: fmt.Sprintf("unable to list the fields of the schema: %s", err)}}
+	}
+	var drifts []drift
+	for _, name := range names {
+		fieldPath := path + "." + name
+		fieldShape, ok := goShape.fields[name]
+		if !ok {
+			drifts = append(drifts, drift{path: fieldPath, reason: "allowed by the schema but cannot be emitted by the Go SDK"})
+			continue
+		}
+		var candidates []cue.Value
+		for _, variant := range variants {
+			if value, isDefined := variant.fields[name]; isDefined {
+				candidates = append(candidates, value)
+			}
+		}
+		drifts = append(drifts, compareCandidates(fieldPath, fieldShape, candidates)...)
+	}
+	for _, name := range sortedFieldNames(goShape) {
+		if slices.Contains(names, name) {
+			continue
+		}
+		fieldPath := path + "." + name
+		var candidates []cue.Value
+		for _, variant := range variants {
+			if variant.value.Allows(cue.Str(name)) {
+				candidates = append(candidates, variant.value.LookupPath(cue.MakePath(cue.AnyString)))
+			}
+		}
+		if len(candidates) == 0 {
+			drifts = append(drifts, drift{path: fieldPath, reason: "emitted by the Go SDK but not allowed by the schema"})
+			continue
+		}
+		drifts = append(drifts, compareCandidates(fieldPath, goShape.fields[name], candidates)...)
+	}
+	return drifts
+}
+
+// compareCandidates compares a Go field with the schemas given to the same field by different variants.
+// It returns nil when the field matches one of them, the closest drifts otherwise.
+func compareCandidates(path string, goShape *shape, candidates []cue.Value) []drift {
+	var closest []drift
+	for i, candidate := range candidates {
+		drifts := compareElem(path, goShape, candidate)
+		if len(drifts) == 0 {
+			return nil
+		}
+		if i == 0 || len(drifts) < len(closest) {
+			closest = drifts
+		}
+	}
+	return closest
+}
+
+// structVariants returns the structs accepted by the schema and the names of their fields, in order of appearance.
+// The schema is evaluated before being split, so that the disjuncts given as references to definitions, or embedded
+// in a struct, are resolved.
+func structVariants(schema cue.Value) ([]structVariant, []string, error) {
+	var variants []structVariant
+	var names []string
+	for _, value := range disjuncts(schema) {
+		if value.IncompleteKind()&cue.StructKind == 0 {
+			continue
+		}
+		iter, err := value.Fields(cue.Optional(true))
+		if err != nil {
+			return nil, nil, err
+		}
+		variant := structVariant{value: value, fields: make(map[string]cue.Value)}
+		for iter.Next() {
+			sel := iter.Selector()
+			if !sel.IsString() {
+				continue
+			}
+			name := sel.Unquoted()
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+			variant.fields[name] = iter.Value()
+		}
+		variants = append(variants, variant)
+	}
+	return variants, names, nil
+}
+
+func disjuncts(schema cue.Value) []cue.Value {
+	if _, err := schema.Fields(cue.Optional(true)); err == nil {
+		return []cue.Value{schema}
+	}
+	op, values := schema.Eval().Expr()
+	if op != cue.OrOp {
+		return []cue.Value{schema}
+	}
+	var result []cue.Value
+	for _, value := range values {
+		result = append(result, disjuncts(value)...)
+	}
+	return result
+}
+
+func expectedKind(kind shapeKind) cue.Kind {
+	switch kind {
+	case stringShape:
+		return cue.StringKind
+	case numberShape:
+		return cue.NumberKind
+	case boolShape:
+		return cue.BoolKind
+	case listShape:
+		return cue.ListKind
+	case structShape:
+		return cue.StructKind
+	default:
+		return cue.TopKind
+	}
+}
+
+func sortedFieldNames(s *shape) []string {
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
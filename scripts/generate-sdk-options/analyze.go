@@ -0,0 +1,739 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	goSDKPathPrefix = "github.com/perses/perses/go-sdk/"
+	timePath        = "time"
+)
+
+// durationPaths are the packages providing the Duration type of the specs, set from a time.Duration by the options.
+var durationPaths = map[string]bool{
+	"github.com/perses/spec/go/common":       true,
+	"github.com/perses/perses/go-sdk/common": true,
+}
+
+// The forms of parameter recognized in the options, named after the constants of the generator package.
+const (
+	valueParam    = "valueParam"
+	pointerParam  = "pointerParam"
+	variadicParam = "variadicParam"
+	selectorParam = "selectorParam"
+	durationParam = "durationParam"
+)
+
+// paramRank orders the forms of parameter, the simplest one being used when several options set the same field.
+var paramRank = map[string]int{
+	valueParam:    0,
+	pointerParam:  1,
+	variadicParam: 2,
+	selectorParam: 3,
+	durationParam: 4,
+}
+
+// option is a functional option setting a single field of a plugin spec from its parameter.
+type option struct {
+	Field string
+	Fn    string
+	Param string
+	// Kind is the kind of datasource selected by a selectorParam option.
+	Kind string
+}
+
+// constructor describes how to build a plugin with the SDK package of its kind.
+type constructor struct {
+	Kind string
+	Path string
+	Name string
+	Fn   string
+	// Args are the options applied by the constructor to its positional arguments, in order.
+	Args []option
+	// Options are the options of the package setting a field of the spec, indexed by field.
+	Options []option
+	// Defaults are the fields set by the constructor when they are not given.
+	Defaults []string
+}
+
+// pkg is a parsed package of a Go SDK.
+type pkg struct {
+	name   string
+	consts map[string]string
+	funcs  map[string]*ast.FuncDecl
+	types  map[string]*ast.TypeSpec
+	// imports are the imports of the file declaring each function, indexed by package name.
+	imports map[*ast.FuncDecl]map[string]string
+}
+
+// analyzer reads the Go SDK of the plugin modules of the repository.
+type analyzer struct {
+	// modules are the directories of the plugin modules, indexed by module path.
+	modules map[string]string
+	pkgs    map[string]*pkg
+}
+
+func newAnalyzer(workspaces []string) (*analyzer, error) {
+	a := &analyzer{modules: make(map[string]string), pkgs: make(map[string]*pkg)}
+	for _, workspace := range workspaces {
+		modulePath, err := readModulePath(filepath.Join(workspace, "go.mod"))
+		if os.IsNotExist(err) {
+			// the plugin doesn't have any Go code
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		a.modules[modulePath] = workspace
+	}
+	return a, nil
+}
+
+// analyzeWorkspace returns the constructor of every plugin registered by the registry.go file of the workspace.
+// The kinds whose constructor or options cannot be recognized are ignored.
+func (a *analyzer) analyzeWorkspace(workspace string) ([]constructor, error) {
+	if _, err := os.Stat(filepath.Join(workspace, "registry.go")); os.IsNotExist(err) {
+		return nil, nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(workspace, "registry.go"), nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := a.fileImports(file)
+	if err != nil {
+		return nil, err
+	}
+	var result []constructor
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall || len(call.Args) != 3 {
+			return true
+		}
+		kindConst, isKind := call.Args[0].(*ast.SelectorExpr)
+		spec, isSpec := call.Args[2].(*ast.CompositeLit)
+		if !isKind || !isSpec {
+			return true
+		}
+		specType, isSpecType := spec.Type.(*ast.SelectorExpr)
+		if !isSpecType {
+			return true
+		}
+		importPath := imports[identName(kindConst.X)]
+		if importPath == "" || imports[identName(specType.X)] != importPath {
+			return true
+		}
+		c, ok, analyzeErr := a.analyzeKind(importPath, kindConst.Sel.Name, specType.Sel.Name)
+		if analyzeErr != nil {
+			err = analyzeErr
+			return false
+		}
+		if ok {
+			result = append(result, c)
+		}
+		return true
+	})
+	return result, err
+}
+
+// analyzeKind finds the constructor of the plugin declared by the given constant and spec type of the package.
+func (a *analyzer) analyzeKind(importPath string, kindConst string, specType string) (constructor, bool, error) {
+	p, err := a.load(importPath)
+	if err != nil {
+		return constructor{}, false, err
+	}
+	kind, ok := p.consts[kindConst]
+	if !ok {
+		return constructor{}, false, nil
+	}
+	fields := p.specFields(specType)
+	var candidates []constructor
+	for _, name := range sortedKeys(p.funcs) {
+		fn := p.funcs[name]
+		optionType, isConstructor := p.constructorOptionType(fn)
+		if !isConstructor || !p.references(fn, kindConst) {
+			continue
+		}
+		options, err := a.options(p, optionType, fields)
+		if err != nil {
+			return constructor{}, false, err
+		}
+		args, defaults, ok := p.constructorArgs(fn, optionType, options)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, constructor{
+			Kind:     kind,
+			Path:     importPath,
+			Name:     p.name,
+			Fn:       name,
+			Args:     args,
+			Options:  bestOptions(options),
+			Defaults: defaults,
+		})
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true, nil
+	}
+	// a package can provide several flavours of the same plugin, e.g. Prometheus, Thanos or Mimir: the one named after
+	// the kind is used.
+	for _, c := range candidates {
+		if strings.HasPrefix(kind, c.Fn) {
+			return c, true, nil
+		}
+	}
+	return constructor{}, false, nil
+}
+
+// options returns every option of the given type setting a field of the spec from its parameter, indexed by name.
+func (a *analyzer) options(p *pkg, optionType string, fields map[string]bool) (map[string]option, error) {
+	result := make(map[string]option)
+	for name, fn := range p.funcs {
+		if !ast.IsExported(name) || fn.Recv != nil || isDeprecated(fn) || !returnsIdent(fn, optionType) {
+			continue
+		}
+		o, ok, err := a.option(p, fn)
+		if err != nil {
+			return nil, err
+		}
+		if ok && fields[o.Field] {
+			result[name] = o
+		}
+	}
+	return result, nil
+}
+
+// option recognizes the options made of a single assignment of their parameter to a field of the builder, e.g.
+//
+//	func Expr(expr string) Option {
+//		return func(builder *Builder) error {
+//			builder.Query = expr
+//			return nil
+//		}
+//	}
+//
+// Checks returning an error can precede the assignment.
+func (a *analyzer) option(p *pkg, fn *ast.FuncDecl) (option, bool, error) {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 || len(fn.Body.List) != 1 {
+		return option{}, false, nil
+	}
+	param := params[0].Names[0].Name
+	_, variadic := params[0].Type.(*ast.Ellipsis)
+	ret, isReturn := fn.Body.List[0].(*ast.ReturnStmt)
+	if !isReturn || len(ret.Results) != 1 {
+		return option{}, false, nil
+	}
+	lit, isLit := ret.Results[0].(*ast.FuncLit)
+	if !isLit || len(lit.Type.Params.List) != 1 || len(lit.Type.Params.List[0].Names) != 1 {
+		return option{}, false, nil
+	}
+	builder := lit.Type.Params.List[0].Names[0].Name
+	var assign *ast.AssignStmt
+	for i, stmt := range lit.Body.List {
+		switch s := stmt.(type) {
+		case *ast.IfStmt:
+			if assigns(s, builder) {
+				return option{}, false, nil
+			}
+		case *ast.AssignStmt:
+			if assign != nil {
+				return option{}, false, nil
+			}
+			assign = s
+		case *ast.ReturnStmt:
+			if i != len(lit.Body.List)-1 || len(s.Results) != 1 || identName(s.Results[0]) != "nil" {
+				return option{}, false, nil
+			}
+		default:
+			return option{}, false, nil
+		}
+	}
+	if assign == nil || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return option{}, false, nil
+	}
+	target, isField := assign.Lhs[0].(*ast.SelectorExpr)
+	if !isField || identName(target.X) != builder {
+		return option{}, false, nil
+	}
+	result := option{Field: target.Sel.Name, Fn: fn.Name.Name}
+	switch value := assign.Rhs[0].(type) {
+	case *ast.Ident:
+		if value.Name != param {
+			return option{}, false, nil
+		}
+		result.Param = valueParam
+		if variadic {
+			result.Param = variadicParam
+		}
+		return result, true, nil
+	case *ast.UnaryExpr:
+		if value.Op != token.AND || identName(value.X) != param || variadic {
+			return option{}, false, nil
+		}
+		result.Param = pointerParam
+		return result, true, nil
+	case *ast.CallExpr:
+		callee, isSelector := value.Fun.(*ast.SelectorExpr)
+		if !isSelector || len(value.Args) != 1 || identName(value.Args[0]) != param || variadic {
+			return option{}, false, nil
+		}
+		calleePath := p.imports[fn][identName(callee.X)]
+		switch {
+		case callee.Sel.Name == "Selector":
+			kind, err := a.selectorKind(calleePath)
+			if err != nil || kind == "" {
+				return option{}, false, err
+			}
+			result.Param = selectorParam
+			result.Kind = kind
+			return result, true, nil
+		case callee.Sel.Name == "Duration" && durationPaths[calleePath] && isType(params[0].Type, p.imports[fn], timePath, "Duration"):
+			result.Param = durationParam
+			return result, true, nil
+		}
+	}
+	return option{}, false, nil
+}
+
+// selectorKind returns the kind of datasource set by the Selector function of the given package, e.g.
+//
+//	func Selector(datasourceName string) *datasource.Selector {
+//		return &datasource.Selector{
+//			Kind: PluginKind,
+//			Name: datasourceName,
+//		}
+//	}
+func (a *analyzer) selectorKind(importPath string) (string, error) {
+	if a.moduleOf(importPath) == "" {
+		return "", nil
+	}
+	p, err := a.load(importPath)
+	if err != nil {
+		return "", err
+	}
+	fn, ok := p.funcs["Selector"]
+	if !ok {
+		return "", nil
+	}
+	var kind string
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		kv, isKeyValue := node.(*ast.KeyValueExpr)
+		if !isKeyValue || identName(kv.Key) != "Kind" {
+			return true
+		}
+		if value, isConst := p.consts[identName(kv.Value)]; isConst {
+			kind = value
+		}
+		return false
+	})
+	return kind, nil
+}
+
+// constructorArgs returns the options applied to the positional arguments of the constructor and the fields set by
+// default. It fails when an argument or a default cannot be matched with an option.
+func (p *pkg) constructorArgs(fn *ast.FuncDecl, optionType string, options map[string]option) ([]option, []string, bool) {
+	create := p.createFunc(optionType)
+	if create == nil {
+		return nil, nil, false
+	}
+	var positional []string
+	params := fn.Type.Params.List
+	for _, field := range params[:len(params)-1] {
+		for _, name := range field.Names {
+			positional = append(positional, name.Name)
+		}
+	}
+	var createArgs []ast.Expr
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		if call, isCall := node.(*ast.CallExpr); isCall && identName(call.Fun) == create.Name.Name {
+			createArgs = call.Args
+		}
+		return true
+	})
+	var createParams []string
+	for _, field := range create.Type.Params.List {
+		for _, name := range field.Names {
+			createParams = append(createParams, name.Name)
+		}
+	}
+	// the options applied by create to its own parameters, indexed by parameter
+	applied := make(map[string]option)
+	var defaults []string
+	unknownDefault := false
+	ast.Inspect(create.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CompositeLit:
+			if array, isArray := n.Type.(*ast.ArrayType); isArray && identName(array.Elt) == optionType {
+				for _, elt := range n.Elts {
+					call, isCall := elt.(*ast.CallExpr)
+					if !isCall {
+						unknownDefault = true
+						continue
+					}
+					o, known := options[identName(call.Fun)]
+					if !known {
+						unknownDefault = true
+						continue
+					}
+					if len(call.Args) == 1 && contains(createParams, identName(call.Args[0])) {
+						applied[identName(call.Args[0])] = o
+						continue
+					}
+					defaults = append(defaults, o.Field)
+				}
+				return false
+			}
+			if identName(n.Type) == "PluginSpec" || strings.HasSuffix(identName(n.Type), "PluginSpec") {
+				for _, elt := range n.Elts {
+					if kv, isKeyValue := elt.(*ast.KeyValueExpr); isKeyValue {
+						defaults = append(defaults, identName(kv.Key))
+					}
+				}
+			}
+		}
+		return true
+	})
+	if unknownDefault {
+		return nil, nil, false
+	}
+	var args []option
+	for _, name := range positional {
+		index := -1
+		for i, arg := range createArgs {
+			if identName(arg) == name {
+				index = i
+			}
+		}
+		if index < 0 || index >= len(createParams) {
+			return nil, nil, false
+		}
+		o, known := applied[createParams[index]]
+		if !known {
+			return nil, nil, false
+		}
+		args = append(args, o)
+	}
+	sort.Strings(defaults)
+	return args, defaults, true
+}
+
+// createFunc returns the function creating the builder from the options of the given type, e.g. `create`.
+func (p *pkg) createFunc(optionType string) *ast.FuncDecl {
+	for _, name := range sortedKeys(p.funcs) {
+		fn := p.funcs[name]
+		if !strings.HasPrefix(name, "create") || fn.Recv != nil {
+			continue
+		}
+		params := fn.Type.Params.List
+		if len(params) == 0 {
+			continue
+		}
+		if ellipsis, isEllipsis := params[len(params)-1].Type.(*ast.Ellipsis); isEllipsis && identName(ellipsis.Elt) == optionType {
+			return fn
+		}
+	}
+	return nil
+}
+
+// constructorOptionType returns the type of the options of a constructor, i.e. an exported function taking variadic
+// options and returning an option of the Go SDK of Perses, e.g. `func Chart(options ...Option) panel.Option`.
+func (p *pkg) constructorOptionType(fn *ast.FuncDecl) (string, bool) {
+	if !fn.Name.IsExported() || fn.Recv != nil || isDeprecated(fn) || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return "", false
+	}
+	result, isSelector := fn.Type.Results.List[0].Type.(*ast.SelectorExpr)
+	if !isSelector || result.Sel.Name != "Option" || !strings.HasPrefix(p.imports[fn][identName(result.X)], goSDKPathPrefix) {
+		return "", false
+	}
+	params := fn.Type.Params.List
+	if len(params) == 0 {
+		return "", false
+	}
+	ellipsis, isEllipsis := params[len(params)-1].Type.(*ast.Ellipsis)
+	if !isEllipsis {
+		return "", false
+	}
+	optionType := identName(ellipsis.Elt)
+	return optionType, optionType != ""
+}
+
+// references tells whether the function uses the identifier, directly or through a function of the package it calls.
+func (p *pkg) references(fn *ast.FuncDecl, ident string) bool {
+	found := false
+	var visit func(node ast.Node, depth int)
+	visit = func(node ast.Node, depth int) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			switch v := n.(type) {
+			case *ast.Ident:
+				found = v.Name == ident
+			case *ast.CallExpr:
+				if callee, isFunc := p.funcs[identName(v.Fun)]; isFunc && depth == 0 {
+					visit(callee.Body, depth+1)
+				}
+			}
+			return !found
+		})
+	}
+	visit(fn.Body, 0)
+	return found
+}
+
+// specFields returns the Go names of the fields of the spec type that are encoded in JSON.
+func (p *pkg) specFields(specType string) map[string]bool {
+	fields := make(map[string]bool)
+	typeSpec, ok := p.types[specType]
+	if !ok {
+		return fields
+	}
+	structType, isStruct := typeSpec.Type.(*ast.StructType)
+	if !isStruct {
+		return fields
+	}
+	for _, field := range structType.Fields.List {
+		if field.Tag != nil {
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err == nil && reflect.StructTag(tag).Get("json") == "-" {
+				continue
+			}
+		}
+		for _, name := range field.Names {
+			if name.IsExported() {
+				fields[name.Name] = true
+			}
+		}
+	}
+	return fields
+}
+
+// load parses the package of the given import path, located in one of the plugin modules.
+func (a *analyzer) load(importPath string) (*pkg, error) {
+	if p, ok := a.pkgs[importPath]; ok {
+		return p, nil
+	}
+	modulePath := a.moduleOf(importPath)
+	if modulePath == "" {
+		return nil, fmt.Errorf("the package %s is not part of a plugin module", importPath)
+	}
+	dir := filepath.Join(a.modules[modulePath], filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, modulePath), "/")))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{
+		consts:  make(map[string]string),
+		funcs:   make(map[string]*ast.FuncDecl),
+		types:   make(map[string]*ast.TypeSpec),
+		imports: make(map[*ast.FuncDecl]map[string]string),
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, parseErr := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		imports, importErr := a.fileImports(file)
+		if importErr != nil {
+			return nil, importErr
+		}
+		p.name = file.Name.Name
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					p.funcs[d.Name.Name] = d
+					p.imports[d] = imports
+				}
+			case *ast.GenDecl:
+				p.addDecl(d)
+			}
+		}
+	}
+	a.pkgs[importPath] = p
+	return p, nil
+}
+
+func (p *pkg) addDecl(decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			p.types[s.Name.Name] = s
+		case *ast.ValueSpec:
+			if decl.Tok != token.CONST {
+				continue
+			}
+			for i, name := range s.Names {
+				if i >= len(s.Values) {
+					continue
+				}
+				if lit, isLit := s.Values[i].(*ast.BasicLit); isLit && lit.Kind == token.STRING {
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						p.consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+}
+
+// fileImports returns the import paths of the file, indexed by package name. The name of the packages imported
+// without alias is read from their source when they are part of a plugin module.
+func (a *analyzer) fileImports(file *ast.File) (map[string]string, error) {
+	imports := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(importPath)
+		switch {
+		case spec.Name != nil:
+			name = spec.Name.Name
+		case a.moduleOf(importPath) != "":
+			p, loadErr := a.load(importPath)
+			if loadErr != nil {
+				return nil, loadErr
+			}
+			name = p.name
+		}
+		imports[name] = importPath
+	}
+	return imports, nil
+}
+
+// moduleOf returns the path of the plugin module providing the package, or an empty string.
+func (a *analyzer) moduleOf(importPath string) string {
+	result := ""
+	for modulePath := range a.modules {
+		if (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) && len(modulePath) > len(result) {
+			result = modulePath
+		}
+	}
+	return result
+}
+
+// bestOptions keeps a single option per field, the one with the simplest parameter, and sorts them by field.
+func bestOptions(options map[string]option) []option {
+	byField := make(map[string]option)
+	for _, name := range sortedKeys(options) {
+		o := options[name]
+		current, exists := byField[o.Field]
+		if !exists || paramRank[o.Param] < paramRank[current.Param] {
+			byField[o.Field] = o
+		}
+	}
+	result := make([]option, 0, len(byField))
+	for _, field := range sortedKeys(byField) {
+		result = append(result, byField[field])
+	}
+	return result
+}
+
+// assigns tells whether the statement assigns a field of the builder.
+func assigns(stmt ast.Stmt, builder string) bool {
+	found := false
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		if assign, isAssign := node.(*ast.AssignStmt); isAssign {
+			for _, lhs := range assign.Lhs {
+				if selector, isSelector := lhs.(*ast.SelectorExpr); isSelector && rootIdent(selector) == builder {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func rootIdent(expr ast.Expr) string {
+	for {
+		selector, isSelector := expr.(*ast.SelectorExpr)
+		if !isSelector {
+			return identName(expr)
+		}
+		expr = selector.X
+	}
+}
+
+func isDeprecated(fn *ast.FuncDecl) bool {
+	return fn.Doc != nil && strings.Contains(fn.Doc.Text(), "Deprecated:")
+}
+
+func returnsIdent(fn *ast.FuncDecl, name string) bool {
+	return fn.Type.Results != nil && len(fn.Type.Results.List) == 1 && identName(fn.Type.Results.List[0].Type) == name
+}
+
+func isType(expr ast.Expr, imports map[string]string, importPath string, name string) bool {
+	selector, isSelector := expr.(*ast.SelectorExpr)
+	return isSelector && selector.Sel.Name == name && imports[identName(selector.X)] == importPath
+}
+
+func identName(expr ast.Expr) string {
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		return ident.Name
+	}
+	return ""
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func readModulePath(goModPath string) (string, error) {
+	data, err := os.ReadFile(goModPath) //nolint: gosec
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modulePath), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module path found in %s", goModPath)
+}
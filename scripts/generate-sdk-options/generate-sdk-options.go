@@ -0,0 +1,125 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/sirupsen/logrus"
+)
+
+const outputPath = "sdk/go/generator/sdk_options.go"
+
+var outputTemplate = template.Must(template.New("sdk_options").Parse(`// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by generate-sdk-options. DO NOT EDIT.
+
+package generator
+
+// sdkConstructors describes the constructor and the options of the SDK package of every plugin registered by the
+// plugin modules, indexed by kind.
+var sdkConstructors = map[string]sdkConstructor{
+{{- range . }}
+	{{ printf "%q" .Kind }}: {
+		path: {{ printf "%q" .Path }},
+		alias: {{ printf "%q" .Name }},
+		fn: {{ printf "%q" .Fn }},
+		{{- if .Args }}
+		args: []sdkOption{
+		{{- range .Args }}
+			{{ template "option" . }},
+		{{- end }}
+		},
+		{{- end }}
+		{{- if .Options }}
+		options: []sdkOption{
+		{{- range .Options }}
+			{{ template "option" . }},
+		{{- end }}
+		},
+		{{- end }}
+		{{- if .Defaults }}
+		defaults: []string{ {{- range $i, $d := .Defaults }}{{ if $i }}, {{ end }}{{ printf "%q" $d }}{{ end -}} },
+		{{- end }}
+	},
+{{- end }}
+}
+{{ define "option" }}{field: {{ printf "%q" .Field }}, fn: {{ printf "%q" .Fn }}, param: {{ .Param }}{{ if .Kind }}, kind: {{ printf "%q" .Kind }}{{ end }}}{{ end }}
+`))
+
+// generate returns the source of sdk_options.go for the given workspaces.
+func generate(workspaces []string) ([]byte, error) {
+	a, err := newAnalyzer(workspaces)
+	if err != nil {
+		return nil, err
+	}
+	var constructors []constructor
+	for _, workspace := range workspaces {
+		result, analyzeErr := a.analyzeWorkspace(workspace)
+		if analyzeErr != nil {
+			return nil, analyzeErr
+		}
+		constructors = append(constructors, result...)
+	}
+	sort.Slice(constructors, func(i, j int) bool {
+		return constructors[i].Kind < constructors[j].Kind
+	})
+	var b bytes.Buffer
+	if execErr := outputTemplate.Execute(&b, constructors); execErr != nil {
+		return nil, execErr
+	}
+	return format.Source(b.Bytes())
+}
+
+// This script generates the file sdk/go/generator/sdk_options.go, describing how the generator can build every plugin
+// registered by the plugin modules with the functional options of its SDK package.
+//
+// The registry.go file of each plugin module gives the kinds and the spec types of its plugins. The SDK package
+// declaring a kind is then parsed to find its constructor (e.g. `table.Table(options ...Option)`) and the options that
+// set a single field of the spec from their parameter. A kind whose constructor is not recognized is not part of the
+// file, the generator builds it from a literal of its spec type instead.
+//
+// Usage:
+//
+//	go run ./scripts/generate-sdk-options
+func main() {
+	workspaces := npm.MustGetWorkspaces(".")
+	for i, workspace := range workspaces {
+		workspaces[i] = filepath.Clean(workspace)
+	}
+	source, err := generate(workspaces)
+	if err != nil {
+		logrus.WithError(err).Fatal("unable to generate the SDK options")
+	}
+	if writeErr := os.WriteFile(outputPath, source, 0644); writeErr != nil { // nolint: gosec
+		logrus.WithError(writeErr).Fatalf("unable to write %s", outputPath)
+	}
+}
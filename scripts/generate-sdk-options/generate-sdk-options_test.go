@@ -0,0 +1,210 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedFileIsUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+	workspaces := npm.MustGetWorkspaces(root)
+	for i, workspace := range workspaces {
+		workspaces[i] = filepath.Join(root, workspace)
+	}
+	expected, err := generate(workspaces)
+	require.NoError(t, err)
+	actual, err := os.ReadFile(filepath.Join(root, outputPath))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "%s is outdated, run `make generate-sdk-options`", outputPath)
+}
+
+func TestAnalyzeWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/fake\n",
+		"registry.go": `package fake
+
+import (
+	"example.com/fake/sdk/go/datasource"
+	chart "example.com/fake/sdk/go"
+)
+
+func Register(register func(kind string, pluginType string, spec any) error) error {
+	if err := register(datasource.PluginKind, "Datasource", datasource.PluginSpec{}); err != nil {
+		return err
+	}
+	return register(chart.PluginKind, "Panel", chart.PluginSpec{})
+}
+`,
+		"sdk/go/datasource/datasource.go": `package datasource
+
+import "github.com/perses/perses/go-sdk/datasource"
+
+const PluginKind = "FakeDatasource"
+
+type PluginSpec struct {
+	URL string ` + "`json:\"url\"`" + `
+}
+
+func Selector(name string) *datasource.Selector {
+	return &datasource.Selector{
+		Kind: PluginKind,
+		Name: name,
+	}
+}
+`,
+		"sdk/go/chart.go": `package fake
+
+import (
+	"fmt"
+	"time"
+
+	"example.com/fake/sdk/go/datasource"
+	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/spec/go/common"
+)
+
+const PluginKind = "FakeChart"
+
+type PluginSpec struct {
+	Title      string               ` + "`json:\"title\"`" + `
+	Mode       *string              ` + "`json:\"mode,omitempty\"`" + `
+	Tags       []string             ` + "`json:\"tags,omitempty\"`" + `
+	Datasource *datasource.Selector ` + "`json:\"datasource,omitempty\"`" + `
+	Step       common.Duration      ` + "`json:\"step,omitempty\"`" + `
+	Legacy     string               ` + "`json:\"-\"`" + `
+}
+
+type Option func(builder *Builder) error
+
+type Builder struct {
+	PluginSpec
+}
+
+func create(title string, options ...Option) (Builder, error) {
+	builder := &Builder{PluginSpec: PluginSpec{}}
+	defaults := []Option{
+		Title(title),
+		Tags("a"),
+	}
+	for _, opt := range append(defaults, options...) {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+	return *builder, nil
+}
+
+func Chart(title string, options ...Option) panel.Option {
+	return func(builder *panel.Builder) error {
+		t, err := create(title, options...)
+		if err != nil {
+			return err
+		}
+		builder.Spec.Plugin.Kind = PluginKind
+		builder.Spec.Plugin.Spec = t
+		return nil
+	}
+}
+
+func Title(title string) Option {
+	return func(builder *Builder) error {
+		if title == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+		builder.Title = title
+		return nil
+	}
+}
+
+func Mode(mode string) Option {
+	return func(builder *Builder) error {
+		builder.Mode = &mode
+		return nil
+	}
+}
+
+func Tags(tags ...string) Option {
+	return func(builder *Builder) error {
+		builder.Tags = tags
+		return nil
+	}
+}
+
+func Datasource(name string) Option {
+	return func(builder *Builder) error {
+		builder.Datasource = datasource.Selector(name)
+		return nil
+	}
+}
+
+func Step(step time.Duration) Option {
+	return func(builder *Builder) error {
+		builder.Step = common.Duration(step)
+		return nil
+	}
+}
+
+// Deprecated: use Title instead.
+func Legacy(legacy string) Option {
+	return func(builder *Builder) error {
+		builder.Legacy = legacy
+		return nil
+	}
+}
+
+func AddTag(tag string) Option {
+	return func(builder *Builder) error {
+		builder.Tags = append(builder.Tags, tag)
+		return nil
+	}
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(workspace, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	a, err := newAnalyzer([]string{workspace})
+	require.NoError(t, err)
+	constructors, err := a.analyzeWorkspace(workspace)
+	require.NoError(t, err)
+	assert.Equal(t, []constructor{
+		{
+			Kind: "FakeChart",
+			Path: "example.com/fake/sdk/go",
+			Name: "fake",
+			Fn:   "Chart",
+			Args: []option{
+				{Field: "Title", Fn: "Title", Param: valueParam},
+			},
+			Options: []option{
+				{Field: "Datasource", Fn: "Datasource", Param: selectorParam, Kind: "FakeDatasource"},
+				{Field: "Mode", Fn: "Mode", Param: pointerParam},
+				{Field: "Step", Fn: "Step", Param: durationParam},
+				{Field: "Tags", Fn: "Tags", Param: variadicParam},
+				{Field: "Title", Fn: "Title", Param: valueParam},
+			},
+			Defaults: []string{"Tags"},
+		},
+	}, constructors)
+}
@@ -17,12 +17,24 @@ import (
 	"slices"
 
 	"github.com/perses/perses/scripts/pkg/npm"
+	"github.com/sirupsen/logrus"
 )
 
-func MustGetWorkspaces(dirPath string) []string {
+func GetWorkspaces(dirPath string) ([]string, error) {
 	excludedWorkspaces := []string{"e2e"}
-	workspaces := npm.MustGetWorkspaces(dirPath)
+	workspaces, err := npm.GetWorkspaces(dirPath)
+	if err != nil {
+		return nil, err
+	}
 	return slices.DeleteFunc(workspaces, func(w string) bool {
 		return slices.Contains(excludedWorkspaces, w)
-	})
+	}), nil
+}
+
+func MustGetWorkspaces(dirPath string) []string {
+	workspaces, err := GetWorkspaces(dirPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("unable to read workspaces from package.json")
+	}
+	return workspaces
 }
@@ -0,0 +1,124 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/perses/plugins/sdk/go/internal/workspace"
+)
+
+// convertProgram registers the plugins of every module and converts the dashboard given as argument with the
+// resulting registry. The result is printed in the standard output as JSON.
+var convertProgram = template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/perses/plugins/sdk/go/generator"
+	"github.com/perses/plugins/sdk/go/registry"
+{{- range $i, $path := .}}
+	plugin{{$i}} "{{$path}}"
+{{- end}}
+)
+
+func main() {
+	var result struct {
+		Source   []byte   ` + "`json:\"source\"`" + `
+		Warnings []string ` + "`json:\"warnings\"`" + `
+		Error    string   ` + "`json:\"error,omitempty\"`" + `
+	}
+	r := registry.New()
+	err := errors.Join(
+{{- range $i, $path := .}}
+		plugin{{$i}}.Register(r.Register),
+{{- end}}
+	)
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(os.Args[1])
+	}
+	if err == nil {
+		result.Source, result.Warnings, err = generator.Generate(data, generator.WithRegistry(r))
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if encodeErr := json.NewEncoder(os.Stdout).Encode(result); encodeErr != nil {
+		panic(encodeErr)
+	}
+}
+`))
+
+type conversion struct {
+	Source   []byte   `json:"source"`
+	Warnings []string `json:"warnings"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// convert converts the dashboard with a registry holding the plugins of every plugin module of the repository cloned
+// in pluginsDir. The root module doesn't depend on the plugin modules, so the conversion is done by a program run in
+// a Go workspace made of the plugin modules.
+func convert(pluginsDir string, file string) ([]byte, []string, error) {
+	modules, err := workspace.PluginModules(pluginsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("--plugins must be the path of a clone of github.com/perses/plugins: %w", err)
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	dir, err := os.MkdirTemp("", "dac-gen")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+	if createErr := workspace.Create(dir, pluginsDir, modules); createErr != nil {
+		return nil, nil, createErr
+	}
+	packages := make([]string, 0, len(modules))
+	for _, module := range modules {
+		packages = append(packages, module.Path)
+	}
+	var program bytes.Buffer
+	if execErr := convertProgram.Execute(&program, packages); execErr != nil {
+		return nil, nil, execErr
+	}
+	if writeErr := os.WriteFile(filepath.Join(dir, "main.go"), program.Bytes(), 0600); writeErr != nil {
+		return nil, nil, writeErr
+	}
+	cmd := workspace.Command(dir, "run", ".", absFile)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if runErr := cmd.Run(); runErr != nil {
+		return nil, nil, fmt.Errorf("unable to run the conversion with the plugin modules: %w\n%s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	var result conversion
+	if decodeErr := json.Unmarshal(stdout.Bytes(), &result); decodeErr != nil {
+		return nil, nil, decodeErr
+	}
+	if result.Error != "" {
+		return nil, result.Warnings, fmt.Errorf("%s", result.Error)
+	}
+	return result.Source, result.Warnings, nil
+}
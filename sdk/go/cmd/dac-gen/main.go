@@ -0,0 +1,52 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// dac-gen converts a Perses dashboard (JSON or YAML) into a Go program building it with the Go SDK.
+// The plugins are converted with the Go SDK of the plugin modules of a clone of github.com/perses/plugins.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the dashboard to convert, in JSON or YAML")
+	out := flag.String("out", "", "path to the Go file to write. The code is printed in the standard output when not set")
+	plugins := flag.String("plugins", ".", "path to a clone of github.com/perses/plugins providing the Go SDK of the plugins")
+	flag.Parse()
+	if len(*file) == 0 {
+		logrus.Fatal("you must provide the dashboard to convert with the --file flag")
+	}
+	if _, err := os.Stat(*file); err != nil {
+		logrus.WithError(err).Fatalf("unable to read the file %s", *file)
+	}
+	source, warnings, err := convert(*plugins, *file)
+	for _, warning := range warnings {
+		logrus.Warn(warning)
+	}
+	if err != nil {
+		logrus.WithError(err).Fatalf("unable to convert the dashboard %s", *file)
+	}
+	if len(*out) == 0 {
+		if _, err := os.Stdout.Write(source); err != nil {
+			logrus.WithError(err).Fatal("unable to print the generated code")
+		}
+		return
+	}
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		logrus.WithError(err).Fatalf("unable to write the file %s", *out)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/perses/plugins/sdk/go/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offlineEnv skips the tests needing the Go dependencies of every plugin module, when they cannot be downloaded:
+//
+//	PERSES_TEST_OFFLINE=true go test ./...
+const offlineEnv = "PERSES_TEST_OFFLINE"
+
+const repositoryRoot = "../../.."
+
+// convertProgram registers the plugins of every module, puts the plugins of the given schema fixtures in a dashboard
+// and prints the code generated from it. The warnings are printed in the standard error.
+var convertProgram = template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/perses/plugins/sdk/go/generator"
+	"github.com/perses/plugins/sdk/go/registry"
+{{- range $i, $path := .}}
+	plugin{{$i}} "{{$path}}"
+{{- end}}
+)
+
+type plugin struct {
+	Kind string ` + "`json:\"kind\"`" + `
+	Spec any    ` + "`json:\"spec\"`" + `
+}
+
+func main() {
+	r := registry.New()
+	if err := errors.Join(
+{{- range $i, $path := .}}
+		plugin{{$i}}.Register(r.Register),
+{{- end}}
+	); err != nil {
+		fail(err)
+	}
+	covered := make(map[string]bool)
+	datasources := map[string]any{}
+	var variables, queries, items, annotations []any
+	panels := map[string]any{}
+	for i, file := range os.Args[1:] {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fail(err)
+		}
+		var p plugin
+		if err := json.Unmarshal(data, &p); err != nil {
+			fail(fmt.Errorf("%s: %w", file, err))
+		}
+		entry, ok := r.Lookup(p.Kind)
+		if !ok {
+			continue
+		}
+		covered[p.Kind] = true
+		name := fmt.Sprintf("plugin%d", i)
+		switch entry.Category {
+		case registry.DatasourceCategory:
+			datasources[name] = map[string]any{"plugin": p}
+		case registry.VariableCategory:
+			variables = append(variables, map[string]any{"kind": "ListVariable", "spec": map[string]any{"name": name, "plugin": p}})
+		case registry.QueryCategory:
+			queries = append(queries, map[string]any{"kind": entry.PluginType, "spec": map[string]any{"plugin": p}})
+		case registry.AnnotationCategory:
+			annotations = append(annotations, map[string]any{"plugin": p})
+		case registry.PanelCategory:
+			panels[name] = map[string]any{"kind": "Panel", "spec": map[string]any{"display": map[string]any{"name": name}, "plugin": p}}
+		}
+	}
+	panels["queries"] = map[string]any{"kind": "Panel", "spec": map[string]any{
+		"display": map[string]any{"name": "queries"},
+		"plugin":  map[string]any{"kind": "Markdown", "spec": map[string]any{"text": "queries"}},
+		"queries": queries,
+	}}
+	for name := range panels {
+		items = append(items, map[string]any{"x": 0, "y": 0, "width": 1, "height": 1, "content": map[string]any{"$ref": "#/spec/panels/" + name}})
+	}
+	data, err := json.Marshal(map[string]any{
+		"kind":     "Dashboard",
+		"metadata": map[string]any{"name": "plugins"},
+		"spec": map[string]any{
+			"datasources": datasources,
+			"variables":   variables,
+			"panels":      panels,
+			"layouts":     []any{map[string]any{"kind": "Grid", "spec": map[string]any{"items": items}}},
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		fail(err)
+	}
+	for _, entry := range r.Entries() {
+		if !covered[entry.Kind] {
+			fmt.Fprintf(os.Stderr, "no fixture for the kind %s\n", entry.Kind)
+		}
+	}
+	source, warnings, err := generator.Generate(data, generator.WithRegistry(r))
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if err != nil {
+		fail(err)
+	}
+	os.Stdout.Write(source)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+`))
+
+// TestGeneratedCodeCompiles converts a dashboard holding the valid schema fixtures of every plugin having a Go SDK,
+// and type checks the generated program against the actual SDKs, in a Go workspace made of the plugin modules.
+func TestGeneratedCodeCompiles(t *testing.T) {
+	if os.Getenv(offlineEnv) == "true" {
+		t.Skipf("%s is set, the Go dependencies of the plugin modules cannot be downloaded", offlineEnv)
+	}
+	modules, err := workspace.PluginModules(repositoryRoot)
+	require.NoError(t, err)
+	packages := make([]string, 0, len(modules))
+	var fixtures []string
+	for _, module := range modules {
+		packages = append(packages, module.Path)
+		moduleFixtures, findErr := findValidFixtures(filepath.Join(module.Dir, "schemas"))
+		require.NoError(t, findErr)
+		for _, fixture := range moduleFixtures {
+			absFixture, absErr := filepath.Abs(fixture)
+			require.NoError(t, absErr)
+			fixtures = append(fixtures, absFixture)
+		}
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, workspace.Create(dir, repositoryRoot, modules))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "convert"), 0700))
+	var program bytes.Buffer
+	require.NoError(t, convertProgram.Execute(&program, packages))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "convert", "main.go"), program.Bytes(), 0600))
+
+	source, warnings := runGo(t, dir, append([]string{"run", "./convert"}, fixtures...)...)
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, "no fixture for the kind") {
+			t.Log(warning)
+			continue
+		}
+		// a fixture can set a value the Go SDK cannot decode or emit, such as a zero value dropped by `omitempty`: the
+		// generator keeps its raw spec. Any other warning means the code of a plugin cannot be generated.
+		if !strings.Contains(warning, "cannot be represented by the type") {
+			assert.Contains(t, warning, "unable to decode the spec")
+		}
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dashboard"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboard", "main.go"), []byte(source), 0600))
+	runGo(t, dir, "vet", "./dashboard")
+}
+
+// runGo runs the go command in the workspace and returns its standard output and the lines of its standard error.
+func runGo(t *testing.T, dir string, args ...string) (string, []string) {
+	cmd := workspace.Command(dir, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	require.NoError(t, cmd.Run(), "go %s:\n%s", args[0], stderr.String())
+	var lines []string
+	scanner := bufio.NewScanner(&stderr)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return stdout.String(), lines
+}
+
+// findValidFixtures returns the valid test files of the model schemas, the migration tests being excluded.
+func findValidFixtures(schemasDir string) ([]string, error) {
+	var fixtures []string
+	err := filepath.WalkDir(schemasDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == "migrate" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".json" && filepath.Base(filepath.Dir(path)) == "valid" {
+			fixtures = append(fixtures, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return fixtures, err
+}
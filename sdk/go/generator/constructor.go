@@ -0,0 +1,197 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/perses/plugins/sdk/go/registry"
+)
+
+// paramForm tells how the parameter of an option is turned into the field of the spec it sets.
+type paramForm int
+
+const (
+	// valueParam options set the field to their parameter.
+	valueParam paramForm = iota
+	// pointerParam options set the field to a pointer to their parameter.
+	pointerParam
+	// variadicParam options set the field to the list of their variadic parameters.
+	variadicParam
+	// selectorParam options set the field to a selector of the datasource named by their parameter.
+	selectorParam
+	// durationParam options set the field to the duration given as a time.Duration.
+	durationParam
+)
+
+// sdkOption is a functional option of a plugin SDK package setting a single field of the spec.
+type sdkOption struct {
+	// field is the Go name of the field of the spec.
+	field string
+	fn    string
+	param paramForm
+	// kind is the kind of datasource selected by a selectorParam option.
+	kind string
+}
+
+// sdkConstructor describes how to build a registered plugin with its SDK package. The constructors of the plugins of
+// this repository are generated in sdk_options.go by the script generate-sdk-options.
+type sdkConstructor struct {
+	path  string
+	alias string
+	fn    string
+	// args are the fields given as positional arguments to the constructor, in order.
+	args    []sdkOption
+	options []sdkOption
+	// defaults are the fields the constructor sets when they are not given.
+	defaults []string
+}
+
+// constructorPlugin renders a registered plugin with the constructor and the options of its SDK package, e.g.
+// `table.Table(table.WithDensity("compact"))`. Like typedPlugin, the spec is decoded with the registry first, then
+// every field set in the decoded value must be covered by an argument or an option of the constructor.
+func (g *generator) constructorPlugin(entry registry.Entry, c sdkConstructor, spec any) (string, error) {
+	decoded, err := g.registry.Decode(entry.Kind, spec)
+	if err != nil {
+		return "", err
+	}
+	if roundTripErr := checkRoundTrip(spec, decoded); roundTripErr != nil {
+		return "", fmt.Errorf("the spec cannot be represented by the type %s: %w", entry.SpecType, roundTripErr)
+	}
+	v := reflect.ValueOf(decoded)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("the type %s is not supported by the options of %s", entry.SpecType, c.path)
+	}
+	// the fields set in the spec, indexed by Go name
+	remaining := make(map[string]any)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsZero() {
+			remaining[v.Type().Field(i).Name] = true
+		}
+	}
+	for _, d := range c.defaults {
+		if _, set := remaining[d]; !set {
+			return "", fmt.Errorf("the field %s would be set by default by %s", d, c.fn)
+		}
+	}
+	pkg := g.use(c.path, c.alias)
+	var args []string
+	for _, arg := range c.args {
+		rendered, renderErr := g.renderOptionParam(arg, v.FieldByName(arg.field))
+		if renderErr != nil {
+			return "", fmt.Errorf("%s: %w", arg.field, renderErr)
+		}
+		delete(remaining, arg.field)
+		args = append(args, rendered...)
+	}
+	for _, opt := range c.options {
+		if _, set := remaining[opt.field]; !set {
+			continue
+		}
+		rendered, renderErr := g.renderOptionParam(opt, v.FieldByName(opt.field))
+		if renderErr != nil {
+			return "", fmt.Errorf("%s: %w", opt.field, renderErr)
+		}
+		delete(remaining, opt.field)
+		if len(rendered) == 1 {
+			args = append(args, fmt.Sprintf("%s.%s(%s)", pkg, opt.fn, rendered[0]))
+		} else {
+			args = append(args, call(pkg+"."+opt.fn, rendered))
+		}
+	}
+	if len(remaining) > 0 {
+		return "", fmt.Errorf("the field %s cannot be set with the options of %s", sortedKeys(remaining)[0], c.path)
+	}
+	return call(pkg+"."+c.fn, args), nil
+}
+
+// renderOptionParam renders the parameters of an option setting the given field.
+func (g *generator) renderOptionParam(opt sdkOption, field reflect.Value) ([]string, error) {
+	if !field.IsValid() {
+		return nil, fmt.Errorf("the field is not part of the spec")
+	}
+	switch opt.param {
+	case pointerParam:
+		if field.Kind() != reflect.Pointer || field.IsNil() {
+			return nil, fmt.Errorf("expected a pointer, got %s", field.Type())
+		}
+		rendered, err := g.renderValue(field.Elem(), false)
+		return []string{rendered}, err
+	case variadicParam:
+		if field.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("expected a list, got %s", field.Type())
+		}
+		items := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			rendered, err := g.renderValue(field.Index(i), false)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			items = append(items, rendered)
+		}
+		return items, nil
+	case selectorParam:
+		name, err := selectorName(opt.kind, field)
+		return []string{strconv.Quote(name)}, err
+	case durationParam:
+		data, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, err
+		}
+		var duration string
+		if unmarshalErr := json.Unmarshal(data, &duration); unmarshalErr != nil {
+			return nil, unmarshalErr
+		}
+		rendered, err := durationType{}.render(g, duration)
+		return []string{rendered}, err
+	default:
+		rendered, err := g.renderValue(field, false)
+		return []string{rendered}, err
+	}
+}
+
+// selectorName returns the name of the datasource selected by the field, which must select a datasource of the given
+// kind (or the default datasource of the query) as the Datasource options do.
+func selectorName(kind string, field reflect.Value) (string, error) {
+	for field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Struct {
+		return "", fmt.Errorf("expected a datasource selector, got %s", field.Type())
+	}
+	var name string
+	for i := 0; i < field.NumField(); i++ {
+		value := field.Field(i)
+		if value.IsZero() {
+			continue
+		}
+		switch fieldName := field.Type().Field(i).Name; {
+		case fieldName == "Name" && value.Kind() == reflect.String:
+			name = value.String()
+		case fieldName == "Kind" && value.Kind() == reflect.String:
+			if value.String() != kind {
+				return "", fmt.Errorf("the datasource kind %q cannot be selected, expected %q", value.String(), kind)
+			}
+		default:
+			return "", fmt.Errorf("unexpected field %s in datasource selector", fieldName)
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,650 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generator converts a Perses dashboard into the source of a Go program building the same dashboard with the
+// Go SDK of Perses and of the plugins.
+//
+// Every plugin whose kind is known by the generator is converted into the functional options of its SDK package
+// (e.g. `timeseries.Chart(timeseries.WithLegend(...))`). The plugins registered with WithRegistry are converted into
+// the options of their SDK package as well when possible, or built from a literal of the Go type of their spec
+// otherwise, and the other plugins are kept as raw specs.
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perses/plugins/sdk/go/registry"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	goSDKPath         = "github.com/perses/perses/go-sdk"
+	dashboardPath     = "github.com/perses/perses/go-sdk/dashboard"
+	datasourcePath    = "github.com/perses/perses/go-sdk/datasource"
+	linkPath          = "github.com/perses/perses/go-sdk/link"
+	panelPath         = "github.com/perses/perses/go-sdk/panel"
+	panelGroupPath    = "github.com/perses/perses/go-sdk/panel-group"
+	queryPath         = "github.com/perses/perses/go-sdk/query"
+	listVariablePath  = "github.com/perses/perses/go-sdk/variable/list-variable"
+	textVariablePath  = "github.com/perses/perses/go-sdk/variable/text-variable"
+	pluginSpecPath    = "github.com/perses/spec/go/plugin"
+	dashboardSpecPath = "github.com/perses/spec/go/dashboard"
+)
+
+// idPattern matches the names accepted as metadata name by Perses.
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+var descriptors = map[registry.Category]map[string]pluginDescriptor{
+	registry.PanelCategory:      indexDescriptors(panelDescriptors),
+	registry.QueryCategory:      indexDescriptors(queryDescriptors),
+	registry.VariableCategory:   indexDescriptors(variableDescriptors),
+	registry.DatasourceCategory: indexDescriptors(datasourceDescriptors),
+	registry.AnnotationCategory: indexDescriptors(annotationDescriptors),
+}
+
+// importSet tracks the packages used by the generated code and gives each of them a unique name.
+type importSet struct {
+	byPath  map[string]string
+	byAlias map[string]string
+}
+
+func newImportSet() *importSet {
+	s := &importSet{
+		byPath:  make(map[string]string),
+		byAlias: make(map[string]string),
+	}
+	// identifiers declared by the generated code
+	for _, name := range []string{"main", "exec", "builder", "buildErr", "ptr"} {
+		s.byAlias[name] = ""
+	}
+	return s
+}
+
+// use returns the name of the package in the generated code, importing it if needed.
+func (s *importSet) use(importPath string, alias string) string {
+	if name, ok := s.byPath[importPath]; ok {
+		return name
+	}
+	name := alias
+	for i := 2; ; i++ {
+		if _, taken := s.byAlias[name]; !taken {
+			break
+		}
+		name = fmt.Sprintf("%s%d", alias, i)
+	}
+	s.byPath[importPath] = name
+	s.byAlias[name] = importPath
+	return name
+}
+
+func (s *importSet) clone() *importSet {
+	result := &importSet{
+		byPath:  make(map[string]string, len(s.byPath)),
+		byAlias: make(map[string]string, len(s.byAlias)),
+	}
+	for k, v := range s.byPath {
+		result.byPath[k] = v
+	}
+	for k, v := range s.byAlias {
+		result.byAlias[k] = v
+	}
+	return result
+}
+
+// render writes the import declaration, the standard library first.
+func (s *importSet) render() string {
+	var std, others []string
+	for importPath := range s.byPath {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			others = append(others, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for i, group := range [][]string{std, others} {
+		if i > 0 && len(std) > 0 && len(others) > 0 {
+			b.WriteString("\n")
+		}
+		for _, importPath := range group {
+			if name := s.byPath[importPath]; name != path.Base(importPath) {
+				fmt.Fprintf(&b, "%s %q\n", name, importPath)
+			} else {
+				fmt.Fprintf(&b, "%q\n", importPath)
+			}
+		}
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+type generator struct {
+	imports      *importSet
+	usePtrHelper bool
+	warnings     []string
+	registry     *registry.Registry
+	// constructors are the SDK constructors of the registered kinds, indexed by kind.
+	constructors map[string]sdkConstructor
+}
+
+// Option configures the generator.
+type Option func(g *generator)
+
+// WithRegistry gives the Go types of the plugins registered in r to the generator. A plugin of a registered kind is
+// converted into the functional options of its SDK package when the generator knows them (see sdk_options.go), and is
+// built from a literal of its Go type (e.g. `&table.PluginSpec{...}`) otherwise, instead of being kept as a raw spec.
+func WithRegistry(r *registry.Registry) Option {
+	return func(g *generator) {
+		g.registry = r
+	}
+}
+
+func (g *generator) use(importPath string, alias string) string {
+	return g.imports.use(importPath, alias)
+}
+
+func (g *generator) warn(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// warnUnsupported records a warning for every field of the object that is not in the list of supported fields.
+func (g *generator) warnUnsupported(location string, m map[string]any, supported ...string) {
+	for _, key := range sortedKeys(m) {
+		found := false
+		for _, s := range supported {
+			if key == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			g.warn("%s.%s is not supported by the generator and has been dropped", location, key)
+		}
+	}
+}
+
+// Generate converts a dashboard, in JSON or YAML, into the source of a Go program building it with the Go SDK.
+// The returned warnings describe every part of the dashboard that has been dropped or kept as a raw spec.
+func Generate(data []byte, opts ...Option) ([]byte, []string, error) {
+	var dash map[string]any
+	if err := yaml.Unmarshal(data, &dash); err != nil {
+		return nil, nil, fmt.Errorf("unable to decode the dashboard: %w", err)
+	}
+	if kind, _ := dash["kind"].(string); kind != "Dashboard" {
+		return nil, nil, fmt.Errorf("expected a dashboard, got the kind %q", kind)
+	}
+	metadata := object(dash["metadata"])
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		return nil, nil, fmt.Errorf("metadata.name of the dashboard cannot be empty")
+	}
+
+	g := &generator{imports: newImportSet(), constructors: sdkConstructors}
+	for _, opt := range opts {
+		opt(g)
+	}
+	options, err := g.dashboardOptions(metadata, object(dash["spec"]))
+	if err != nil {
+		return nil, g.warnings, err
+	}
+	flagName := g.use("flag", "flag")
+	sdkName := g.use(goSDKPath, "sdk")
+	dashboardName := g.use(dashboardPath, "dashboard")
+
+	var b strings.Builder
+	b.WriteString("package main\n\n")
+	b.WriteString(g.imports.render())
+	b.WriteString("\nfunc main() {\n")
+	fmt.Fprintf(&b, "%s.Parse()\n", flagName)
+	fmt.Fprintf(&b, "exec := %s.NewExec()\n", sdkName)
+	fmt.Fprintf(&b, "builder, buildErr := %s\n", call(dashboardName+".New", append([]string{strconv.Quote(name)}, options...)))
+	b.WriteString("exec.BuildDashboard(builder, buildErr)\n")
+	b.WriteString("}\n")
+	if g.usePtrHelper {
+		b.WriteString("\nfunc ptr[T any](v T) *T {\nreturn &v\n}\n")
+	}
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, g.warnings, fmt.Errorf("unable to format the generated code: %w", err)
+	}
+	return source, g.warnings, nil
+}
+
+func (g *generator) dashboardOptions(metadata map[string]any, spec map[string]any) ([]string, error) {
+	g.warnUnsupported("metadata", metadata, "name", "project", "createdAt", "updatedAt", "version")
+	g.warnUnsupported("spec", spec, "display", "duration", "refreshInterval", "datasources", "variables", "panels", "layouts", "annotations")
+	dashboardName := g.use(dashboardPath, "dashboard")
+	var options []string
+	if project, _ := metadata["project"].(string); project != "" {
+		options = append(options, fmt.Sprintf("%s.ProjectName(%q)", dashboardName, project))
+	}
+	display := object(spec["display"])
+	g.warnUnsupported("spec.display", display, "name", "description")
+	if displayName, _ := display["name"].(string); displayName != "" && displayName != metadata["name"] {
+		if idPattern.MatchString(displayName) {
+			// dashboard.Name would override the metadata name
+			g.warn("spec.display.name %q is a valid name, it cannot be set with the Go SDK and has been dropped", displayName)
+		} else {
+			options = append(options, fmt.Sprintf("%s.Name(%q)", dashboardName, displayName))
+		}
+	}
+	if description, _ := display["description"].(string); description != "" {
+		options = append(options, fmt.Sprintf("%s.Description(%q)", dashboardName, description))
+	}
+	if duration, _ := spec["duration"].(string); duration != "" {
+		options = append(options, fmt.Sprintf("%s.DurationAsString(%q)", dashboardName, duration))
+	}
+	if refreshInterval, _ := spec["refreshInterval"].(string); refreshInterval != "" {
+		options = append(options, fmt.Sprintf("%s.RefreshIntervalAsString(%q)", dashboardName, refreshInterval))
+	}
+
+	datasourceOptions, err := g.datasources(object(spec["datasources"]))
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, datasourceOptions...)
+	for i, v := range list(spec["variables"]) {
+		option, err := g.variable(fmt.Sprintf("spec.variables[%d]", i), object(v))
+		if err != nil {
+			return nil, err
+		}
+		if option != "" {
+			options = append(options, option)
+		}
+	}
+	layoutOptions, err := g.layouts(object(spec["panels"]), list(spec["layouts"]))
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, layoutOptions...)
+	for i, a := range list(spec["annotations"]) {
+		option, err := g.annotation(fmt.Sprintf("spec.annotations[%d]", i), object(a))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+func (g *generator) datasources(datasourceSpecs map[string]any) ([]string, error) {
+	var options []string
+	for _, name := range sortedKeys(datasourceSpecs) {
+		location := fmt.Sprintf("spec.datasources.%s", name)
+		spec := object(datasourceSpecs[name])
+		g.warnUnsupported(location, spec, "default", "plugin")
+		args := []string{strconv.Quote(name)}
+		if isDefault, _ := spec["default"].(bool); isDefault {
+			args = append(args, fmt.Sprintf("%s.Default(true)", g.use(datasourcePath, "datasource")))
+		}
+		pluginOption, err := g.plugin(location+".plugin", registry.DatasourceCategory, object(spec["plugin"]), func(raw string) string {
+			return fmt.Sprintf("%s.Plugin(%s)", g.use(datasourcePath, "datasource"), raw)
+		})
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, pluginOption)
+		options = append(options, call(g.use(dashboardPath, "dashboard")+".AddDatasource", args))
+	}
+	return options, nil
+}
+
+func (g *generator) variable(location string, v map[string]any) (string, error) {
+	kind, _ := v["kind"].(string)
+	spec := object(v["spec"])
+	name, _ := spec["name"].(string)
+	display := object(spec["display"])
+	g.warnUnsupported(location+".spec.display", display, "name", "description", "hidden")
+	var variableOption string
+	switch kind {
+	case "ListVariable":
+		g.warnUnsupported(location+".spec", spec, "name", "display", "defaultValue", "allowAllValue", "allowMultiple", "customAllValue", "capturingRegexp", "sort", "plugin")
+		listName := g.use(listVariablePath, "listvariable")
+		pluginOption, err := g.plugin(location+".spec.plugin", registry.VariableCategory, object(spec["plugin"]), func(raw string) string {
+			return fmt.Sprintf("func(builder *%s.Builder) error {\nbuilder.ListVariableSpec.Plugin = %s\nreturn nil\n}", listName, raw)
+		})
+		if err != nil {
+			return "", err
+		}
+		args := append([]string{pluginOption}, g.displayOptions(listName, display)...)
+		for _, flagOption := range []struct{ json, fn string }{{"allowAllValue", "AllowAllValue"}, {"allowMultiple", "AllowMultiple"}} {
+			if value, _ := spec[flagOption.json].(bool); value {
+				args = append(args, fmt.Sprintf("%s.%s(true)", listName, flagOption.fn))
+			}
+		}
+		for _, stringOption := range []struct{ json, fn string }{{"customAllValue", "CustomAllValue"}, {"capturingRegexp", "CapturingRegexp"}, {"sort", "SortingBy"}} {
+			if value, _ := spec[stringOption.json].(string); value != "" {
+				args = append(args, fmt.Sprintf("%s.%s(%q)", listName, stringOption.fn, value))
+			}
+		}
+		switch defaultValue := spec["defaultValue"].(type) {
+		case string:
+			args = append(args, fmt.Sprintf("%s.DefaultValue(%q)", listName, defaultValue))
+		case []any:
+			values, err := renderItems(g, stringType{}, defaultValue)
+			if err != nil {
+				return "", fmt.Errorf("%s.spec.defaultValue: %w", location, err)
+			}
+			args = append(args, fmt.Sprintf("%s.DefaultValues(%s)", listName, values))
+		}
+		variableOption = call(listName+".List", args)
+	case "TextVariable":
+		g.warnUnsupported(location+".spec", spec, "name", "display", "value", "constant")
+		textName := g.use(textVariablePath, "textvariable")
+		value, _ := spec["value"].(string)
+		args := []string{strconv.Quote(value)}
+		if constant, _ := spec["constant"].(bool); constant {
+			args = append(args, fmt.Sprintf("%s.Constant(true)", textName))
+		}
+		args = append(args, g.displayOptions(textName, display)...)
+		variableOption = call(textName+".Text", args)
+	default:
+		g.warn("%s: the variable kind %q is not supported by the generator, the variable has been dropped", location, kind)
+		return "", nil
+	}
+	return call(g.use(dashboardPath, "dashboard")+".AddVariable", []string{strconv.Quote(name), variableOption}), nil
+}
+
+// displayOptions renders the display of a variable, both list and text variables providing the same options.
+func (g *generator) displayOptions(pkg string, display map[string]any) []string {
+	var options []string
+	if name, _ := display["name"].(string); name != "" {
+		options = append(options, fmt.Sprintf("%s.DisplayName(%q)", pkg, name))
+	}
+	if description, _ := display["description"].(string); description != "" {
+		options = append(options, fmt.Sprintf("%s.Description(%q)", pkg, description))
+	}
+	if hidden, _ := display["hidden"].(bool); hidden {
+		options = append(options, fmt.Sprintf("%s.Hidden(true)", pkg))
+	}
+	return options
+}
+
+func (g *generator) layouts(panelSpecs map[string]any, layouts []any) ([]string, error) {
+	used := make(map[string]bool)
+	var options []string
+	for i, l := range layouts {
+		location := fmt.Sprintf("spec.layouts[%d]", i)
+		layout := object(l)
+		if kind, _ := layout["kind"].(string); kind != "Grid" {
+			g.warn("%s: the layout kind %q is not supported by the generator, the layout has been dropped", location, kind)
+			continue
+		}
+		spec := object(layout["spec"])
+		g.warnUnsupported(location+".spec", spec, "display", "items", "repeatVariable")
+		display := object(spec["display"])
+		title, _ := display["title"].(string)
+		var positions, groupOptions []string
+		if collapse, ok := display["collapse"].(map[string]any); ok {
+			open, _ := collapse["open"].(bool)
+			groupOptions = append(groupOptions, fmt.Sprintf("%s.Collapsed(%t)", g.use(panelGroupPath, "panelgroup"), !open))
+		}
+		if repeatVariable, _ := spec["repeatVariable"].(string); repeatVariable != "" {
+			groupOptions = append(groupOptions, fmt.Sprintf("%s.RepeatVariable(%q)", g.use(panelGroupPath, "panelgroup"), repeatVariable))
+		}
+		for j, it := range list(spec["items"]) {
+			itemLocation := fmt.Sprintf("%s.spec.items[%d]", location, j)
+			item := object(it)
+			ref, _ := object(item["content"])["$ref"].(string)
+			panelRef := strings.TrimPrefix(ref, "#/spec/panels/")
+			panelSpec, ok := panelSpecs[panelRef]
+			if !ok || panelRef == ref {
+				g.warn("%s: the reference %q does not match any panel, the item has been dropped", itemLocation, ref)
+				continue
+			}
+			used[panelRef] = true
+			panelOption, err := g.panel(fmt.Sprintf("spec.panels.%s", panelRef), object(panelSpec))
+			if err != nil {
+				return nil, err
+			}
+			groupOptions = append(groupOptions, panelOption)
+			positions = append(positions, fmt.Sprintf("{X: %d, Y: %d, W: %d, H: %d}", integer(item["x"]), integer(item["y"]), integer(item["width"]), integer(item["height"])))
+		}
+		dashboardName := g.use(dashboardPath, "dashboard")
+		gridItems := fmt.Sprintf("[]%s.GridItem{}", dashboardName)
+		if len(positions) > 0 {
+			gridItems = fmt.Sprintf("[]%s.GridItem{\n%s,\n}", dashboardName, strings.Join(positions, ",\n"))
+		}
+		options = append(options, call(dashboardName+".AddCustomPanelGroup", append([]string{strconv.Quote(title), gridItems}, groupOptions...)))
+	}
+	for _, panelRef := range sortedKeys(panelSpecs) {
+		if !used[panelRef] {
+			g.warn("spec.panels.%s is not part of any layout and has been dropped", panelRef)
+		}
+	}
+	return options, nil
+}
+
+func (g *generator) panel(location string, p map[string]any) (string, error) {
+	spec := object(p["spec"])
+	g.warnUnsupported(location+".spec", spec, "display", "plugin", "queries", "links")
+	display := object(spec["display"])
+	g.warnUnsupported(location+".spec.display", display, "name", "description")
+	title, _ := display["name"].(string)
+	var options []string
+	if description, _ := display["description"].(string); description != "" {
+		options = append(options, fmt.Sprintf("%s.Description(%q)", g.use(panelPath, "panel"), description))
+	}
+	pluginOption, err := g.plugin(location+".spec.plugin", registry.PanelCategory, object(spec["plugin"]), func(raw string) string {
+		return fmt.Sprintf("%s.Plugin(%s)", g.use(panelPath, "panel"), raw)
+	})
+	if err != nil {
+		return "", err
+	}
+	options = append(options, pluginOption)
+	for i, q := range list(spec["queries"]) {
+		queryOption, err := g.query(fmt.Sprintf("%s.spec.queries[%d]", location, i), object(q))
+		if err != nil {
+			return "", err
+		}
+		options = append(options, fmt.Sprintf("%s.AddQuery(\n%s,\n)", g.use(panelPath, "panel"), queryOption))
+	}
+	for i, l := range list(spec["links"]) {
+		options = append(options, g.link(fmt.Sprintf("%s.spec.links[%d]", location, i), object(l)))
+	}
+	return call(g.use(panelGroupPath, "panelgroup")+".AddPanel", append([]string{strconv.Quote(title)}, options...)), nil
+}
+
+func (g *generator) query(location string, q map[string]any) (string, error) {
+	kind, _ := q["kind"].(string)
+	spec := object(q["spec"])
+	g.warnUnsupported(location+".spec", spec, "plugin")
+	return g.plugin(location+".spec.plugin", registry.QueryCategory, object(spec["plugin"]), func(raw string) string {
+		return fmt.Sprintf("%s.Option{\nKind: %s.Kind(%q),\nPlugin: %s,\n}", g.use(queryPath, "query"), g.use(pluginSpecPath, "plugin"), kind, raw)
+	})
+}
+
+func (g *generator) link(location string, l map[string]any) string {
+	g.warnUnsupported(location, l, "url", "name", "tooltip", "renderVariables", "targetBlank")
+	url, _ := l["url"].(string)
+	args := []string{strconv.Quote(url)}
+	for _, stringOption := range []struct{ json, fn string }{{"name", "Name"}, {"tooltip", "Tooltip"}} {
+		if value, _ := l[stringOption.json].(string); value != "" {
+			args = append(args, fmt.Sprintf("%s.%s(%q)", g.use(linkPath, "link"), stringOption.fn, value))
+		}
+	}
+	for _, flagOption := range []struct{ json, fn string }{{"renderVariables", "RenderVariable"}, {"targetBlank", "TargetBlank"}} {
+		if value, _ := l[flagOption.json].(bool); value {
+			args = append(args, fmt.Sprintf("%s.%s(true)", g.use(linkPath, "link"), flagOption.fn))
+		}
+	}
+	return call(g.use(panelPath, "panel")+".AddLink", args)
+}
+
+func (g *generator) annotation(location string, a map[string]any) (string, error) {
+	g.warnUnsupported(location, a, "plugin")
+	return g.plugin(location+".plugin", registry.AnnotationCategory, object(a["plugin"]), func(raw string) string {
+		return fmt.Sprintf("func(builder *%s.Builder) error {\nbuilder.Dashboard.Spec.Annotations = append(builder.Dashboard.Spec.Annotations, %s.AnnotationSpec{\nPlugin: %s,\n})\nreturn nil\n}",
+			g.use(dashboardPath, "dashboard"), g.use(dashboardSpecPath, "dashboardSpec"), raw)
+	})
+}
+
+// plugin renders a plugin with the SDK package of its kind. When the kind is unknown or when the spec uses a field the
+// generator cannot convert, the plugin is built from the options of the SDK package registered for its kind (see
+// WithRegistry), then from a literal of its registered Go type, or from its raw spec otherwise. The resulting
+// plugin.Plugin literal is given to wrap to build the option.
+func (g *generator) plugin(location string, category registry.Category, p map[string]any, wrap func(plugin string) string) (string, error) {
+	kind, _ := p["kind"].(string)
+	var reason error
+	if d, known := descriptors[category][kind]; known {
+		result, err := g.attempt(func() (string, error) {
+			return g.knownPlugin(d, object(p["spec"]))
+		})
+		if err == nil {
+			return result, nil
+		}
+		reason = err
+	}
+	entry, registered := g.lookup(kind)
+	if c, known := g.constructors[kind]; known && registered && entry.Category == category {
+		result, err := g.attempt(func() (string, error) {
+			return g.constructorPlugin(entry, c, p["spec"])
+		})
+		if err == nil {
+			return result, nil
+		}
+		reason = err
+	}
+	if registered && entry.Category == category {
+		result, err := g.attempt(func() (string, error) {
+			return g.typedPlugin(entry, p["spec"])
+		})
+		if err == nil {
+			return wrap(result), nil
+		}
+		reason = err
+	}
+	if reason != nil {
+		g.warn("%s: %s, the raw spec of the plugin %q is used", location, reason, kind)
+	}
+	raw, err := g.rawPlugin(kind, p["spec"])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", location, err)
+	}
+	return wrap(raw), nil
+}
+
+// attempt runs render and rolls back the imports it used when it fails.
+func (g *generator) attempt(render func() (string, error)) (string, error) {
+	imports := g.imports.clone()
+	usePtrHelper := g.usePtrHelper
+	result, err := render()
+	if err != nil {
+		g.imports = imports
+		g.usePtrHelper = usePtrHelper
+	}
+	return result, err
+}
+
+func (g *generator) lookup(kind string) (registry.Entry, bool) {
+	if g.registry == nil {
+		return registry.Entry{}, false
+	}
+	return g.registry.Lookup(kind)
+}
+
+func (g *generator) knownPlugin(d pluginDescriptor, spec map[string]any) (string, error) {
+	pkg := g.use(d.path, d.alias)
+	remaining := make(map[string]any, len(spec))
+	for key, value := range spec {
+		remaining[key] = value
+	}
+	var args []string
+	for _, arg := range d.args {
+		value, ok := remaining[arg.json]
+		if !ok {
+			return "", fmt.Errorf("the field %q is missing", arg.json)
+		}
+		delete(remaining, arg.json)
+		rendered, err := arg.typ.render(g, value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", arg.json, err)
+		}
+		args = append(args, rendered)
+	}
+	for _, opt := range d.options {
+		value, ok := remaining[opt.json]
+		if !ok {
+			continue
+		}
+		delete(remaining, opt.json)
+		var rendered string
+		var err error
+		if opt.variadic {
+			items, isList := value.([]any)
+			if !isList {
+				return "", fmt.Errorf("%s: expected a list, got %T", opt.json, value)
+			}
+			rendered, err = renderItems(g, opt.typ, items)
+		} else {
+			rendered, err = opt.typ.render(g, value)
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", opt.json, err)
+		}
+		args = append(args, fmt.Sprintf("%s.%s(%s)", pkg, opt.fn, rendered))
+	}
+	if len(remaining) > 0 {
+		return "", fmt.Errorf("the field %q is not supported by the generator", sortedKeys(remaining)[0])
+	}
+	return call(pkg+"."+d.constructor, args), nil
+}
+
+func (g *generator) rawPlugin(kind string, spec any) (string, error) {
+	fields := []string{fmt.Sprintf("Kind: %q", kind)}
+	if spec != nil {
+		rendered, err := rawType{}.render(g, spec)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, "Spec: "+rendered)
+	}
+	return fmt.Sprintf("%s.Plugin{\n%s,\n}", g.use(pluginSpecPath, "plugin"), strings.Join(fields, ",\n")), nil
+}
+
+// call renders a function call with one argument per line.
+func call(fn string, args []string) string {
+	if len(args) == 0 {
+		return fn + "()"
+	}
+	return fmt.Sprintf("%s(\n%s,\n)", fn, strings.Join(args, ",\n"))
+}
+
+func object(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func list(value any) []any {
+	l, _ := value.([]any)
+	return l
+}
+
+func integer(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,595 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/perses/plugins/sdk/go/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardYAML = `
+kind: Dashboard
+metadata:
+  name: node
+  project: infra
+spec:
+  display:
+    name: Node Exporter
+  duration: 6h
+  refreshInterval: 30s
+  datasources:
+    prom:
+      default: true
+      plugin:
+        kind: PrometheusDatasource
+        spec:
+          directUrl: http://localhost:9090
+  variables:
+    - kind: ListVariable
+      spec:
+        name: instance
+        display:
+          name: Instance
+        allowMultiple: true
+        defaultValue: [a, b]
+        plugin:
+          kind: PrometheusLabelValuesVariable
+          spec:
+            labelName: instance
+            matchers: ['up{job="node"}']
+    - kind: TextVariable
+      spec:
+        name: job
+        value: node
+        constant: true
+  panels:
+    cpu:
+      kind: Panel
+      spec:
+        display:
+          name: CPU
+        plugin:
+          kind: TimeSeriesChart
+          spec:
+            legend:
+              position: bottom
+              values: [last]
+            yAxis:
+              format:
+                unit: percent
+        queries:
+          - kind: TimeSeriesQuery
+            spec:
+              plugin:
+                kind: PrometheusTimeSeriesQuery
+                spec:
+                  query: rate(node_cpu_seconds_total[5m])
+                  seriesNameFormat: '{{instance}}'
+                  minStep: 1m
+                  datasource:
+                    kind: PrometheusDatasource
+                    name: prom
+        links:
+          - url: https://example.com
+            targetBlank: true
+    logs:
+      kind: Panel
+      spec:
+        display:
+          name: Logs
+        plugin:
+          kind: LogsTable
+          spec:
+            wrap: true
+        queries:
+          - kind: LogQuery
+            spec:
+              plugin:
+                kind: LokiLogQuery
+                spec:
+                  query: '{job="node"}'
+  layouts:
+    - kind: Grid
+      spec:
+        display:
+          title: Overview
+          collapse:
+            open: true
+        items:
+          - x: 0
+            y: 0
+            width: 12
+            height: 8
+            content:
+              $ref: '#/spec/panels/cpu'
+          - x: 12
+            y: 0
+            width: 12
+            height: 8
+            content:
+              $ref: '#/spec/panels/logs'
+`
+
+func generate(t *testing.T, data string) (string, []string) {
+	source, warnings, err := Generate([]byte(data))
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", source, parser.AllErrors)
+	require.NoError(t, err, string(source))
+	return string(source), warnings
+}
+
+func TestGenerate(t *testing.T) {
+	source, warnings := generate(t, dashboardYAML)
+	assert.Empty(t, warnings)
+	for _, expected := range []string{
+		`dashboard.New(
+		"node",`,
+		`dashboard.ProjectName("infra")`,
+		`dashboard.Name("Node Exporter")`,
+		`dashboard.DurationAsString("6h")`,
+		`promDatasource.Prometheus(
+				promDatasource.DirectURL("http://localhost:9090"),
+			)`,
+		`datasource.Default(true)`,
+		`labelvalues.PrometheusLabelValues(
+					"instance",
+					labelvalues.Matchers(`,
+		`listvariable.DefaultValues(`,
+		`textvariable.Constant(true)`,
+		`[]dashboard.GridItem{
+				{X: 0, Y: 0, W: 12, H: 8},
+				{X: 12, Y: 0, W: 12, H: 8},
+			}`,
+		`panelgroup.Collapsed(false)`,
+		`timeseries.WithLegend(timeseries.Legend{
+						Position: "bottom",
+						Values: []common.Calculation{
+							"last",
+						},
+					})`,
+		`Format: &common.Format{
+							Unit: ptr[string]("percent"),
+						},`,
+		`promQuery.SeriesNameFormat("{{instance}}")`,
+		`promQuery.MinStep(time.Minute)`,
+		`promQuery.Datasource("prom")`,
+		`panel.AddLink(
+					"https://example.com",
+					link.TargetBlank(true),
+				)`,
+		// unknown kinds are kept as raw specs
+		`panel.Plugin(plugin.Plugin{
+					Kind: "LogsTable",
+					Spec: map[string]any{
+						"wrap": true,
+					},
+				})`,
+		`query.Option{
+						Kind: plugin.Kind("LogQuery"),`,
+		`func ptr[T any](v T) *T {`,
+	} {
+		assert.Contains(t, source, expected)
+	}
+}
+
+func TestGenerateMappings(t *testing.T) {
+	source, warnings := generate(t, `
+kind: Dashboard
+metadata:
+  name: mappings
+spec:
+  panels:
+    stat:
+      kind: Panel
+      spec:
+        display:
+          name: Stat
+        plugin:
+          kind: StatChart
+          spec:
+            calculation: last
+            mappings:
+              - kind: Value
+                spec:
+                  value: "1"
+                  result:
+                    value: up
+              - kind: Range
+                spec:
+                  from: 0
+                  result:
+                    value: low
+                    color: red
+  layouts:
+    - kind: Grid
+      spec:
+        items:
+          - x: 0
+            y: 0
+            width: 6
+            height: 4
+            content:
+              $ref: '#/spec/panels/stat'
+`)
+	assert.Empty(t, warnings)
+	assert.Contains(t, source, `stat.Mappings(
+						stat.Mapping{
+							Kind: "Value",
+							Spec: &stat.ValueMappingSpec{
+								Result: stat.MappingResult{
+									Value: "up",
+								},
+								Value: "1",
+							},
+						},
+						stat.Mapping{
+							Kind: "Range",
+							Spec: &stat.RangeMappingSpec{
+								From: ptr[float64](0),
+								Result: stat.MappingResult{
+									Color: "red",
+									Value: "low",
+								},
+							},
+						},
+					)`)
+}
+
+func TestGenerateFallback(t *testing.T) {
+	source, warnings := generate(t, `
+kind: Dashboard
+metadata:
+  name: fallback
+spec:
+  panels:
+    gauge:
+      kind: Panel
+      spec:
+        display:
+          name: Gauge
+        plugin:
+          kind: GaugeChart
+          spec:
+            calculation: last
+            sparkline: {}
+  layouts:
+    - kind: Grid
+      spec:
+        items:
+          - x: 0
+            y: 0
+            width: 6
+            height: 4
+            content:
+              $ref: '#/spec/panels/gauge'
+  annotations:
+    - plugin:
+        kind: CustomAnnotation
+        spec:
+          query: up
+`)
+	// the gauge package is only imported when the typed options are used
+	assert.NotContains(t, source, strconv.Quote(gaugeChartPath))
+	assert.Contains(t, source, `Kind: "GaugeChart"`)
+	assert.Contains(t, source, `dashboardSpec.AnnotationSpec{`)
+	assert.Equal(t, []string{
+		`spec.panels.gauge.spec.plugin: the field "sparkline" is not supported by the generator, the raw spec of the plugin "GaugeChart" is used`,
+	}, warnings)
+}
+
+type FakeColumn struct {
+	Name  string   `json:"name"`
+	Width *float64 `json:"width,omitempty"`
+	Hide  bool     `json:"hide,omitempty"`
+}
+
+type FakeDensity string
+
+type FakeTableSpec struct {
+	Density  FakeDensity       `json:"density,omitempty"`
+	Columns  []FakeColumn      `json:"columns,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Wrap     *bool             `json:"wrap,omitempty"`
+	Settings any               `json:"settings,omitempty"`
+}
+
+func TestGenerateWithRegistry(t *testing.T) {
+	r := registry.New()
+	require.NoError(t, r.Register("FakeTable", "Panel", FakeTableSpec{}))
+	require.NoError(t, r.Register("FakeQuery", "TimeSeriesQuery", FakeTableSpec{}))
+	dashboard := `
+kind: Dashboard
+metadata:
+  name: registry
+spec:
+  panels:
+    table:
+      kind: Panel
+      spec:
+        display:
+          name: Table
+        plugin:
+          kind: %s
+          spec:
+            density: compact
+            columns:
+              - name: value
+                width: 100
+            headers:
+              b: "2"
+              a: "1"
+            wrap: true
+            settings:
+              limit: 10
+              labels: [a]
+  layouts:
+    - kind: Grid
+      spec:
+        items:
+          - x: 0
+            y: 0
+            width: 6
+            height: 4
+            content:
+              $ref: '#/spec/panels/table'
+`
+	source, warnings, err := Generate([]byte(fmt.Sprintf(dashboard, "FakeTable")), WithRegistry(r))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Contains(t, string(source), `panel.Plugin(plugin.Plugin{
+					Kind: "FakeTable",
+					Spec: &generator.FakeTableSpec{
+						Density: "compact",
+						Columns: []generator.FakeColumn{
+							{
+								Name:  "value",
+								Width: ptr[float64](100),
+							},
+						},
+						Headers: map[string]string{
+							"a": "1",
+							"b": "2",
+						},
+						Wrap: ptr[bool](true),
+						Settings: map[string]any{
+							"labels": []any{
+								"a",
+							},
+							"limit": float64(10),
+						},
+					},
+				})`)
+
+	t.Run("unknown field", func(t *testing.T) {
+		source, warnings, err := Generate([]byte(strings.Replace(fmt.Sprintf(dashboard, "FakeTable"), "wrap: true", "wrap: true\n            unknown: true", 1)), WithRegistry(r))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`spec.panels.table.spec.plugin: the spec cannot be represented by the type generator.FakeTableSpec: the field spec.unknown is dropped, the raw spec of the plugin "FakeTable" is used`,
+		}, warnings)
+		assert.Contains(t, string(source), `"unknown": true,`)
+	})
+
+	t.Run("category mismatch", func(t *testing.T) {
+		source, warnings, err := Generate([]byte(fmt.Sprintf(dashboard, "FakeQuery")), WithRegistry(r))
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.NotContains(t, string(source), "generator.FakeTableSpec")
+	})
+}
+
+func withConstructors(constructors map[string]sdkConstructor) Option {
+	return func(g *generator) {
+		g.constructors = constructors
+	}
+}
+
+func TestGenerateWithSDKOptions(t *testing.T) {
+	r := registry.New()
+	require.NoError(t, r.Register("FakeTable", "Panel", FakeTableSpec{}))
+	fakeTable := sdkConstructor{
+		path:  "example.com/fake/table",
+		alias: "table",
+		fn:    "Table",
+		args: []sdkOption{
+			{field: "Density", fn: "WithDensity", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Columns", fn: "Columns", param: variadicParam},
+			{field: "Headers", fn: "WithHeaders", param: valueParam},
+			{field: "Wrap", fn: "Wrap", param: pointerParam},
+		},
+	}
+	dashboard := `
+kind: Dashboard
+metadata:
+  name: options
+spec:
+  panels:
+    table:
+      kind: Panel
+      spec:
+        display:
+          name: Table
+        plugin:
+          kind: FakeTable
+          spec:
+            density: compact
+            columns:
+              - name: value
+              - name: time
+                hide: true
+            wrap: true
+  layouts:
+    - kind: Grid
+      spec:
+        items:
+          - x: 0
+            y: 0
+            width: 6
+            height: 4
+            content:
+              $ref: '#/spec/panels/table'
+`
+	source, warnings, err := Generate([]byte(dashboard), WithRegistry(r), withConstructors(map[string]sdkConstructor{"FakeTable": fakeTable}))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Contains(t, string(source), `"example.com/fake/table"`)
+	assert.Contains(t, string(source), `table.Table(
+					"compact",
+					table.Columns(
+						generator.FakeColumn{
+							Name: "value",
+						},
+						generator.FakeColumn{
+							Name: "time",
+							Hide: true,
+						},
+					),
+					table.Wrap(true),
+				),`)
+
+	t.Run("field without option", func(t *testing.T) {
+		source, warnings, err := Generate([]byte(strings.Replace(dashboard, "wrap: true", "wrap: true\n            headers:\n              a: \"1\"", 1)), WithRegistry(r),
+			withConstructors(map[string]sdkConstructor{"FakeTable": {path: fakeTable.path, alias: fakeTable.alias, fn: fakeTable.fn, args: fakeTable.args}}))
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.NotContains(t, string(source), `"example.com/fake/table"`)
+		assert.Contains(t, string(source), "Spec: &generator.FakeTableSpec{")
+	})
+
+	t.Run("default not given", func(t *testing.T) {
+		withDefault := fakeTable
+		withDefault.defaults = []string{"Headers"}
+		source, warnings, err := Generate([]byte(dashboard), WithRegistry(r), withConstructors(map[string]sdkConstructor{"FakeTable": withDefault}))
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.NotContains(t, string(source), `"example.com/fake/table"`)
+		assert.Contains(t, string(source), "Spec: &generator.FakeTableSpec{")
+	})
+}
+
+type FakeSelector struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type FakeQuerySpec struct {
+	Datasource *FakeSelector `json:"datasource,omitempty"`
+	Step       string        `json:"step,omitempty"`
+}
+
+func TestConstructorPluginParams(t *testing.T) {
+	r := registry.New()
+	require.NoError(t, r.Register("FakeQuery", "TimeSeriesQuery", FakeQuerySpec{}))
+	entry, _ := r.Lookup("FakeQuery")
+	fakeQuery := sdkConstructor{
+		path:  "example.com/fake/query",
+		alias: "query",
+		fn:    "Query",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "FakeDatasource"},
+			{field: "Step", fn: "Step", param: durationParam},
+		},
+	}
+	testSuite := []struct {
+		title    string
+		spec     map[string]any
+		expected string
+		err      string
+	}{
+		{
+			title:    "selector and duration",
+			spec:     map[string]any{"datasource": map[string]any{"kind": "FakeDatasource", "name": "prom"}, "step": "5m"},
+			expected: "query.Query(\nquery.Datasource(\"prom\"),\nquery.Step(5 * time.Minute),\n)",
+		},
+		{
+			title:    "selector without kind",
+			spec:     map[string]any{"datasource": map[string]any{"name": "prom"}},
+			expected: "query.Query(\nquery.Datasource(\"prom\"),\n)",
+		},
+		{
+			title: "selector of another kind",
+			spec:  map[string]any{"datasource": map[string]any{"kind": "OtherDatasource", "name": "prom"}},
+			err:   `Datasource: the datasource kind "OtherDatasource" cannot be selected, expected "FakeDatasource"`,
+		},
+		{
+			title: "invalid duration",
+			spec:  map[string]any{"step": "$interval"},
+			err:   `Step: time: invalid duration "$interval"`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			g := &generator{imports: newImportSet(), registry: r}
+			result, err := g.constructorPlugin(entry, fakeQuery, test.spec)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestGenerateWarnings(t *testing.T) {
+	_, warnings := generate(t, `
+kind: Dashboard
+metadata:
+  name: warnings
+spec:
+  display:
+    name: other_name
+  panels:
+    orphan:
+      kind: Panel
+      spec:
+        plugin:
+          kind: Markdown
+          spec:
+            text: hello
+  variables:
+    - kind: CustomVariable
+      spec:
+        name: custom
+`)
+	assert.Equal(t, []string{
+		`spec.display.name "other_name" is a valid name, it cannot be set with the Go SDK and has been dropped`,
+		`spec.variables[0]: the variable kind "CustomVariable" is not supported by the generator, the variable has been dropped`,
+		`spec.panels.orphan is not part of any layout and has been dropped`,
+	}, warnings)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, _, err := Generate([]byte(`{"kind": "Datasource", "metadata": {"name": "prom"}}`))
+	assert.Error(t, err)
+	_, _, err = Generate([]byte(`{"kind": "Dashboard", "metadata": {}}`))
+	assert.Error(t, err)
+}
+
+func TestImportSet(t *testing.T) {
+	s := newImportSet()
+	assert.Equal(t, "query", s.use(queryPath, "query"))
+	assert.Equal(t, "query", s.use(queryPath, "query"))
+	assert.Equal(t, "query2", s.use(prometheusQueryPath, "query"))
+	assert.Equal(t, "exec2", s.use("os/exec", "exec"))
+}
@@ -0,0 +1,361 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+const (
+	goSDKCommonPath = "github.com/perses/perses/go-sdk/common"
+
+	prometheusDatasourcePath  = "github.com/perses/plugins/prometheus/sdk/go/datasource"
+	prometheusQueryPath       = "github.com/perses/plugins/prometheus/sdk/go/query"
+	prometheusAnnotationPath  = "github.com/perses/plugins/prometheus/sdk/go/annotation"
+	prometheusLabelNamesPath  = "github.com/perses/plugins/prometheus/sdk/go/variable/label-names"
+	prometheusLabelValuesPath = "github.com/perses/plugins/prometheus/sdk/go/variable/label-values"
+	prometheusPromQLPath      = "github.com/perses/plugins/prometheus/sdk/go/variable/promql"
+	staticListPath            = "github.com/perses/plugins/staticlistvariable/sdk/go"
+	timeSeriesChartPath       = "github.com/perses/plugins/timeserieschart/sdk/go"
+	statChartPath             = "github.com/perses/plugins/statchart/sdk/go"
+	gaugeChartPath            = "github.com/perses/plugins/gaugechart/sdk/go"
+	markdownPath              = "github.com/perses/plugins/markdown/sdk/go"
+)
+
+// option maps a field of a plugin spec to a functional option (or a positional argument) of the plugin SDK package.
+type option struct {
+	json string
+	fn   string
+	typ  valueType
+	// variadic is set when a list is given to the option as variadic arguments.
+	variadic bool
+}
+
+// pluginDescriptor describes how to build a plugin with its Go SDK package.
+type pluginDescriptor struct {
+	kind        string
+	path        string
+	alias       string
+	constructor string
+	// args are the fields given as positional arguments to the constructor, in order.
+	args    []option
+	options []option
+}
+
+var (
+	formatType = structType{
+		ref: typeRef{path: goSDKCommonPath, alias: "common", name: "Format"},
+		fields: []field{
+			{json: "unit", goName: "Unit", typ: ptrType{ref: builtin("string"), elem: stringType{}}},
+			{json: "decimalPlaces", goName: "DecimalPlaces", typ: numberType{integer: true}},
+			{json: "shortValues", goName: "ShortValues", typ: boolType{}},
+		},
+	}
+	thresholdsType = structType{
+		ref: typeRef{path: goSDKCommonPath, alias: "common", name: "Thresholds"},
+		fields: []field{
+			{json: "mode", goName: "Mode", typ: stringType{}},
+			{json: "defaultColor", goName: "DefaultColor", typ: stringType{}},
+			{json: "steps", goName: "Steps", typ: listType{
+				ref: typeRef{path: goSDKCommonPath, alias: "common", name: "StepOption"},
+				elem: structType{
+					// the type of the items is elided in the composite literal of the list
+					ref: builtin(""),
+					fields: []field{
+						{json: "value", goName: "Value", typ: numberType{}},
+						{json: "color", goName: "Color", typ: stringType{}},
+						{json: "name", goName: "Name", typ: stringType{}},
+					},
+				},
+			}},
+		},
+	}
+	calculationType   = stringType{}
+	mappingResultType = structType{
+		ref: statChartType("MappingResult"),
+		fields: []field{
+			{json: "value", goName: "Value", typ: stringType{}},
+			{json: "color", goName: "Color", typ: stringType{}},
+		},
+	}
+	// mappingSpecTypes are the types of the spec of the value mappings, by kind of mapping.
+	mappingSpecTypes = map[string]structType{
+		"Value": {
+			ref: statChartType("ValueMappingSpec"),
+			fields: []field{
+				{json: "value", goName: "Value", typ: stringType{}},
+				{json: "result", goName: "Result", typ: mappingResultType},
+			},
+		},
+		"Range": {
+			ref: statChartType("RangeMappingSpec"),
+			fields: []field{
+				{json: "from", goName: "From", typ: ptrType{ref: builtin("float64"), elem: numberType{}}},
+				{json: "to", goName: "To", typ: ptrType{ref: builtin("float64"), elem: numberType{}}},
+				{json: "result", goName: "Result", typ: mappingResultType},
+			},
+		},
+		"Regex": {
+			ref: statChartType("RegexMappingSpec"),
+			fields: []field{
+				{json: "pattern", goName: "Pattern", typ: stringType{}},
+				{json: "result", goName: "Result", typ: mappingResultType},
+			},
+		},
+		"Misc": {
+			ref: statChartType("MiscMappingSpec"),
+			fields: []field{
+				{json: "value", goName: "Value", typ: stringType{}},
+				{json: "result", goName: "Result", typ: mappingResultType},
+			},
+		},
+	}
+)
+
+func timeSeriesChartType(name string) typeRef {
+	return typeRef{path: timeSeriesChartPath, alias: "timeseries", name: name}
+}
+
+func statChartType(name string) typeRef {
+	return typeRef{path: statChartPath, alias: "stat", name: name}
+}
+
+func gaugeChartType(name string) typeRef {
+	return typeRef{path: gaugeChartPath, alias: "gauge", name: name}
+}
+
+var panelDescriptors = []pluginDescriptor{
+	{
+		kind:        "TimeSeriesChart",
+		path:        timeSeriesChartPath,
+		alias:       "timeseries",
+		constructor: "Chart",
+		options: []option{
+			{json: "legend", fn: "WithLegend", typ: structType{
+				ref: timeSeriesChartType("Legend"),
+				fields: []field{
+					{json: "position", goName: "Position", typ: stringType{}},
+					{json: "mode", goName: "Mode", typ: stringType{}},
+					{json: "size", goName: "Size", typ: stringType{}},
+					{json: "values", goName: "Values", typ: listType{ref: typeRef{path: goSDKCommonPath, alias: "common", name: "Calculation"}, elem: calculationType}},
+				},
+			}},
+			{json: "tooltip", fn: "WithTooltip", typ: structType{
+				ref: timeSeriesChartType("Tooltip"),
+				fields: []field{
+					{json: "enablePinning", goName: "EnablePinning", typ: boolType{}},
+				},
+			}},
+			{json: "yAxis", fn: "WithYAxis", typ: structType{
+				ref: timeSeriesChartType("YAxis"),
+				fields: []field{
+					{json: "show", goName: "Show", typ: boolType{}},
+					{json: "label", goName: "Label", typ: stringType{}},
+					{json: "format", goName: "Format", typ: ptrType{elem: formatType}},
+					{json: "min", goName: "Min", typ: numberType{}},
+					{json: "max", goName: "Max", typ: numberType{}},
+					{json: "logBase", goName: "LogBase", typ: numberType{integer: true}},
+				},
+			}},
+			{json: "thresholds", fn: "Thresholds", typ: thresholdsType},
+			{json: "visual", fn: "WithVisual", typ: structType{
+				ref: timeSeriesChartType("Visual"),
+				fields: []field{
+					{json: "display", goName: "Display", typ: stringType{}},
+					{json: "lineWidth", goName: "LineWidth", typ: numberType{}},
+					{json: "lineStyle", goName: "LineStyle", typ: stringType{}},
+					{json: "areaOpacity", goName: "AreaOpacity", typ: numberType{}},
+					{json: "showPoints", goName: "ShowPoints", typ: stringType{}},
+					{json: "palette", goName: "Palette", typ: ptrType{elem: structType{
+						ref: timeSeriesChartType("Palette"),
+						fields: []field{
+							{json: "mode", goName: "Mode", typ: stringType{}},
+						},
+					}}},
+					{json: "pointRadius", goName: "PointRadius", typ: numberType{}},
+					{json: "stack", goName: "Stack", typ: stringType{}},
+					{json: "connectNulls", goName: "ConnectNulls", typ: boolType{}},
+				},
+			}},
+			{json: "querySettings", fn: "WithQuerySettings", typ: listType{
+				ref: timeSeriesChartType("QuerySettingsItem"),
+				elem: structType{
+					ref: builtin(""),
+					fields: []field{
+						{json: "queryIndex", goName: "QueryIndex", typ: numberType{integer: true}},
+						{json: "colorMode", goName: "ColorMode", typ: stringType{}},
+						{json: "colorValue", goName: "ColorValue", typ: stringType{}},
+						{json: "lineStyle", goName: "LineStyle", typ: stringType{}},
+						{json: "areaOpacity", goName: "AreaOpacity", typ: numberType{}},
+						{json: "format", goName: "Format", typ: ptrType{elem: formatType}},
+						{json: "negativeY", goName: "NegativeY", typ: boolType{}},
+						{json: "stack", goName: "Stack", typ: ptrType{ref: builtin("bool"), elem: boolType{}}},
+					},
+				},
+			}},
+		},
+	},
+	{
+		kind:        "StatChart",
+		path:        statChartPath,
+		alias:       "stat",
+		constructor: "Chart",
+		options: []option{
+			{json: "calculation", fn: "Calculation", typ: calculationType},
+			{json: "metricLabel", fn: "MetricLabel", typ: stringType{}},
+			{json: "format", fn: "Format", typ: formatType},
+			{json: "thresholds", fn: "Thresholds", typ: thresholdsType},
+			{json: "sparkline", fn: "WithSparkline", typ: structType{
+				ref: statChartType("Sparkline"),
+				fields: []field{
+					{json: "color", goName: "Color", typ: stringType{}},
+					{json: "width", goName: "Width", typ: numberType{}},
+				},
+			}},
+			{json: "valueFontSize", fn: "ValueFontSize", typ: numberType{integer: true}},
+			{json: "colorMode", fn: "WithColorMode", typ: stringType{}},
+			{json: "legendMode", fn: "WithLegendMode", typ: stringType{}},
+			{json: "mappings", fn: "Mappings", typ: mappingType{}, variadic: true},
+		},
+	},
+	{
+		kind:        "GaugeChart",
+		path:        gaugeChartPath,
+		alias:       "gauge",
+		constructor: "Chart",
+		options: []option{
+			{json: "calculation", fn: "Calculation", typ: calculationType},
+			{json: "format", fn: "Format", typ: formatType},
+			{json: "thresholds", fn: "Thresholds", typ: thresholdsType},
+			{json: "max", fn: "Max", typ: numberType{}},
+			{json: "legend", fn: "Legend", typ: structType{
+				ref: gaugeChartType("LegendSpec"),
+				fields: []field{
+					{json: "show", goName: "Show", typ: boolType{}},
+				},
+			}},
+		},
+	},
+	{
+		kind:        "Markdown",
+		path:        markdownPath,
+		alias:       "markdown",
+		constructor: "Markdown",
+		args: []option{
+			{json: "text", typ: stringType{}},
+		},
+	},
+}
+
+var queryDescriptors = []pluginDescriptor{
+	{
+		kind:        "PrometheusTimeSeriesQuery",
+		path:        prometheusQueryPath,
+		alias:       "promQuery",
+		constructor: "PromQL",
+		args: []option{
+			{json: "query", typ: stringType{}},
+		},
+		options: []option{
+			{json: "datasource", fn: "Datasource", typ: selectorType{}},
+			{json: "seriesNameFormat", fn: "SeriesNameFormat", typ: stringType{}},
+			{json: "minStep", fn: "MinStep", typ: durationType{}},
+			{json: "resolution", fn: "Resolution", typ: numberType{integer: true}},
+			{json: "instant", fn: "Instant", typ: boolType{}},
+		},
+	},
+}
+
+var variableDescriptors = []pluginDescriptor{
+	{
+		kind:        "PrometheusLabelNamesVariable",
+		path:        prometheusLabelNamesPath,
+		alias:       "labelnames",
+		constructor: "PrometheusLabelNames",
+		options: []option{
+			{json: "datasource", fn: "Datasource", typ: selectorType{}},
+			{json: "matchers", fn: "Matchers", typ: stringType{}, variadic: true},
+		},
+	},
+	{
+		kind:        "PrometheusLabelValuesVariable",
+		path:        prometheusLabelValuesPath,
+		alias:       "labelvalues",
+		constructor: "PrometheusLabelValues",
+		args: []option{
+			{json: "labelName", typ: stringType{}},
+		},
+		options: []option{
+			{json: "datasource", fn: "Datasource", typ: selectorType{}},
+			{json: "matchers", fn: "Matchers", typ: stringType{}, variadic: true},
+		},
+	},
+	{
+		kind:        "PrometheusPromQLVariable",
+		path:        prometheusPromQLPath,
+		alias:       "promql",
+		constructor: "PrometheusPromQL",
+		args: []option{
+			{json: "expr", typ: stringType{}},
+		},
+		options: []option{
+			{json: "labelName", fn: "LabelName", typ: stringType{}},
+			{json: "datasource", fn: "Datasource", typ: selectorType{}},
+		},
+	},
+	{
+		kind:        "StaticListVariable",
+		path:        staticListPath,
+		alias:       "staticlist",
+		constructor: "StaticList",
+		options: []option{
+			{json: "values", fn: "Values", typ: stringType{}, variadic: true},
+		},
+	},
+}
+
+var datasourceDescriptors = []pluginDescriptor{
+	{
+		kind:        "PrometheusDatasource",
+		path:        prometheusDatasourcePath,
+		alias:       "promDatasource",
+		constructor: "Prometheus",
+		options: []option{
+			{json: "directUrl", fn: "DirectURL", typ: stringType{}},
+			{json: "queryParams", fn: "QueryParams", typ: mapType{ref: builtin("string"), elem: stringType{}}},
+		},
+	},
+}
+
+var annotationDescriptors = []pluginDescriptor{
+	{
+		kind:        "PrometheusPromQLAnnotation",
+		path:        prometheusAnnotationPath,
+		alias:       "annotation",
+		constructor: "PrometheusPromQL",
+		args: []option{
+			{json: "expr", typ: stringType{}},
+		},
+		options: []option{
+			{json: "datasource", fn: "Datasource", typ: selectorType{}},
+			{json: "title", fn: "Title", typ: stringType{}},
+			{json: "legend", fn: "Legend", typ: stringType{}},
+			{json: "tags", fn: "Tags", typ: stringType{}, variadic: true},
+		},
+	},
+}
+
+func indexDescriptors(descriptors []pluginDescriptor) map[string]pluginDescriptor {
+	result := make(map[string]pluginDescriptor, len(descriptors))
+	for _, d := range descriptors {
+		result[d.kind] = d
+	}
+	return result
+}
@@ -0,0 +1,587 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by generate-sdk-options. DO NOT EDIT.
+
+package generator
+
+// sdkConstructors describes the constructor and the options of the SDK package of every plugin registered by the
+// plugin modules, indexed by kind.
+var sdkConstructors = map[string]sdkConstructor{
+	"AlertManagerAlertsQuery": {
+		path:  "github.com/perses/plugins/alertmanager/sdk/go/query/alerts",
+		alias: "alerts",
+		fn:    "AlertsQuery",
+		options: []sdkOption{
+			{field: "Active", fn: "Active", param: pointerParam},
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "AlertManagerDatasource"},
+			{field: "Filters", fn: "Filters", param: variadicParam},
+			{field: "Inhibited", fn: "Inhibited", param: pointerParam},
+			{field: "Receiver", fn: "Receiver", param: valueParam},
+			{field: "Silenced", fn: "Silenced", param: pointerParam},
+			{field: "Unprocessed", fn: "Unprocessed", param: pointerParam},
+		},
+	},
+	"AlertManagerSilencesQuery": {
+		path:  "github.com/perses/plugins/alertmanager/sdk/go/query/silences",
+		alias: "silences",
+		fn:    "SilencesQuery",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "AlertManagerDatasource"},
+			{field: "Filters", fn: "Filters", param: variadicParam},
+		},
+	},
+	"AlertTable": {
+		path:  "github.com/perses/plugins/alertmanager/sdk/go/panel",
+		alias: "panel",
+		fn:    "AlertTable",
+		options: []sdkOption{
+			{field: "AllowedActions", fn: "AllowedActions", param: variadicParam},
+			{field: "Columns", fn: "Columns", param: variadicParam},
+			{field: "Deduplication", fn: "Deduplication", param: pointerParam},
+			{field: "DefaultGroupBy", fn: "DefaultGroupBy", param: variadicParam},
+			{field: "LabelColorMappings", fn: "LabelColorMappings", param: variadicParam},
+			{field: "RunbookAnnotationKey", fn: "RunbookAnnotationKey", param: valueParam},
+		},
+	},
+	"BarChart": {
+		path:  "github.com/perses/plugins/barchart/sdk/go",
+		alias: "bar",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Calculation", fn: "Calculation", param: valueParam},
+			{field: "Format", fn: "Format", param: pointerParam},
+			{field: "GroupBy", fn: "WithGroupBy", param: valueParam},
+			{field: "IsStacked", fn: "WithStacked", param: valueParam},
+			{field: "Mode", fn: "WithMode", param: valueParam},
+			{field: "Orientation", fn: "WithOrientation", param: valueParam},
+			{field: "Sort", fn: "SortingBy", param: valueParam},
+		},
+		defaults: []string{"Calculation"},
+	},
+	"ClickHouseLogQuery": {
+		path:  "github.com/perses/plugins/clickhouse/sdk/go/query/log",
+		alias: "log",
+		fn:    "ClickHouseLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "ClickHouseDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"ClickHouseTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/clickhouse/sdk/go/query/time-series",
+		alias: "timeseries",
+		fn:    "ClickHouseTimeSeriesQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "ClickHouseDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"DatasourceVariable": {
+		path:  "github.com/perses/plugins/datasourcevariable/sdk/go",
+		alias: "datasourcevariable",
+		fn:    "Datasource",
+		args: []sdkOption{
+			{field: "DatasourcePluginKind", fn: "DatasourcePluginKind", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "DatasourcePluginKind", fn: "DatasourcePluginKind", param: valueParam},
+		},
+	},
+	"FlameChart": {
+		path:  "github.com/perses/plugins/flamechart/sdk/go",
+		alias: "flamechart",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Palette", fn: "DefinePalette", param: valueParam},
+		},
+	},
+	"GaugeChart": {
+		path:  "github.com/perses/plugins/gaugechart/sdk/go",
+		alias: "gauge",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Calculation", fn: "Calculation", param: valueParam},
+			{field: "Format", fn: "Format", param: pointerParam},
+			{field: "Legend", fn: "Legend", param: pointerParam},
+			{field: "Max", fn: "Max", param: valueParam},
+			{field: "Thresholds", fn: "Thresholds", param: pointerParam},
+		},
+		defaults: []string{"Calculation"},
+	},
+	"GreptimeDBLogQuery": {
+		path:  "github.com/perses/plugins/greptimedb/sdk/go/query/log",
+		alias: "log",
+		fn:    "GreptimeDBLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "GreptimeDBDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"GreptimeDBTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/greptimedb/sdk/go/query/time-series",
+		alias: "timeseries",
+		fn:    "GreptimeDBTimeSeriesQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "GreptimeDBDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"GreptimeDBTraceQuery": {
+		path:  "github.com/perses/plugins/greptimedb/sdk/go/query/trace",
+		alias: "trace",
+		fn:    "GreptimeDBTraceQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "GreptimeDBDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"HeatMapChart": {
+		path:  "github.com/perses/plugins/heatmapchart/sdk/go",
+		alias: "heatmap",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "CountFormat", fn: "CountFormat", param: pointerParam},
+			{field: "LogBase", fn: "WithLogBase", param: valueParam},
+			{field: "Max", fn: "Max", param: valueParam},
+			{field: "Min", fn: "Min", param: valueParam},
+			{field: "ShowVisualMap", fn: "ShowVisualMap", param: valueParam},
+			{field: "YAxisFormat", fn: "YAxisFormat", param: pointerParam},
+		},
+		defaults: []string{"CountFormat", "ShowVisualMap", "YAxisFormat"},
+	},
+	"HistogramChart": {
+		path:  "github.com/perses/plugins/histogramchart/sdk/go",
+		alias: "histogram",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Format", fn: "Format", param: pointerParam},
+			{field: "LogBase", fn: "WithLogBase", param: valueParam},
+			{field: "Max", fn: "Max", param: valueParam},
+			{field: "Min", fn: "Min", param: valueParam},
+			{field: "Thresholds", fn: "Thresholds", param: pointerParam},
+		},
+		defaults: []string{"Format"},
+	},
+	"JaegerTraceQuery": {
+		path:  "github.com/perses/plugins/jaeger/sdk/go/query",
+		alias: "query",
+		fn:    "Trace",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "JaegerDatasource"},
+			{field: "Limit", fn: "Limit", param: pointerParam},
+			{field: "Operation", fn: "Operation", param: valueParam},
+			{field: "Service", fn: "Service", param: valueParam},
+			{field: "TraceID", fn: "TraceID", param: valueParam},
+		},
+	},
+	"LogsTable": {
+		path:  "github.com/perses/plugins/logstable/sdk/go",
+		alias: "logstable",
+		fn:    "LogsTable",
+		options: []sdkOption{
+			{field: "AllowWrap", fn: "AllowWrap", param: pointerParam},
+			{field: "EnableDetails", fn: "EnableDetails", param: pointerParam},
+			{field: "Selection", fn: "WithSelection", param: pointerParam},
+			{field: "ShowTime", fn: "ShowTime", param: pointerParam},
+		},
+	},
+	"LokiLabelNamesVariable": {
+		path:  "github.com/perses/plugins/loki/sdk/go/variable/label-names",
+		alias: "labelnames",
+		fn:    "LokiLabelNames",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "LokiDatasource"},
+			{field: "Matchers", fn: "Matchers", param: variadicParam},
+		},
+	},
+	"LokiLabelValuesVariable": {
+		path:  "github.com/perses/plugins/loki/sdk/go/variable/label-values",
+		alias: "labelvalues",
+		fn:    "LokiLabelValues",
+		args: []sdkOption{
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "LokiDatasource"},
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+			{field: "Matchers", fn: "Matchers", param: variadicParam},
+		},
+	},
+	"LokiLogQLVariable": {
+		path:  "github.com/perses/plugins/loki/sdk/go/variable/logql",
+		alias: "logql",
+		fn:    "LokiLogQL",
+		args: []sdkOption{
+			{field: "Expr", fn: "Expr", param: valueParam},
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "LokiDatasource"},
+			{field: "Expr", fn: "Expr", param: valueParam},
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+		},
+	},
+	"LokiLogQuery": {
+		path:  "github.com/perses/plugins/loki/sdk/go/query/log",
+		alias: "log",
+		fn:    "LokiLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "LokiDatasource"},
+			{field: "Direction", fn: "SetDirection", param: valueParam},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"LokiTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/loki/sdk/go/query/time-series",
+		alias: "timeseries",
+		fn:    "LokiTimeSeriesQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "LokiDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"Markdown": {
+		path:  "github.com/perses/plugins/markdown/sdk/go",
+		alias: "markdown",
+		fn:    "Markdown",
+		args: []sdkOption{
+			{field: "Text", fn: "Text", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Text", fn: "Text", param: valueParam},
+		},
+	},
+	"OpenSearchDatasource": {
+		path:  "github.com/perses/plugins/opensearch/sdk/go/datasource",
+		alias: "datasource",
+		fn:    "OpenSearch",
+		options: []sdkOption{
+			{field: "DirectURL", fn: "DirectURL", param: valueParam},
+		},
+	},
+	"OpenSearchLogQuery": {
+		path:  "github.com/perses/plugins/opensearch/sdk/go/query/log",
+		alias: "log",
+		fn:    "OpenSearchLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "OpenSearchDatasource"},
+			{field: "DisableTimeFilter", fn: "DisableTimeFilter", param: valueParam},
+			{field: "Index", fn: "Index", param: valueParam},
+			{field: "MessageField", fn: "MessageField", param: valueParam},
+			{field: "Query", fn: "Query", param: valueParam},
+			{field: "TimestampField", fn: "TimestampField", param: valueParam},
+		},
+	},
+	"PieChart": {
+		path:  "github.com/perses/plugins/piechart/sdk/go",
+		alias: "pie",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Calculation", fn: "Calculation", param: valueParam},
+			{field: "Format", fn: "WithFormat", param: valueParam},
+			{field: "Legend", fn: "WithLegend", param: pointerParam},
+		},
+		defaults: []string{"Calculation"},
+	},
+	"PrometheusDatasource": {
+		path:  "github.com/perses/plugins/prometheus/sdk/go/datasource",
+		alias: "datasource",
+		fn:    "Prometheus",
+		options: []sdkOption{
+			{field: "DirectURL", fn: "DirectURL", param: valueParam},
+			{field: "ScrapeInterval", fn: "ScrapeInterval", param: durationParam},
+		},
+	},
+	"PrometheusLabelNamesVariable": {
+		path:  "github.com/perses/plugins/prometheus/sdk/go/variable/label-names",
+		alias: "labelnames",
+		fn:    "PrometheusLabelNames",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "PrometheusDatasource"},
+			{field: "Matchers", fn: "Matchers", param: variadicParam},
+		},
+	},
+	"PrometheusLabelValuesVariable": {
+		path:  "github.com/perses/plugins/prometheus/sdk/go/variable/label-values",
+		alias: "labelvalues",
+		fn:    "PrometheusLabelValues",
+		args: []sdkOption{
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "PrometheusDatasource"},
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+			{field: "Matchers", fn: "Matchers", param: variadicParam},
+		},
+	},
+	"PrometheusPromQLVariable": {
+		path:  "github.com/perses/plugins/prometheus/sdk/go/variable/promql",
+		alias: "promql",
+		fn:    "PrometheusPromQL",
+		args: []sdkOption{
+			{field: "Expr", fn: "Expr", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "PrometheusDatasource"},
+			{field: "Expr", fn: "Expr", param: valueParam},
+			{field: "LabelName", fn: "LabelName", param: valueParam},
+		},
+	},
+	"PrometheusTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/prometheus/sdk/go/query",
+		alias: "query",
+		fn:    "PromQL",
+		args: []sdkOption{
+			{field: "Query", fn: "Expr", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "PrometheusDatasource"},
+			{field: "Instant", fn: "Instant", param: pointerParam},
+			{field: "MinStep", fn: "MinStep", param: durationParam},
+			{field: "Query", fn: "Expr", param: valueParam},
+			{field: "Resolution", fn: "Resolution", param: valueParam},
+			{field: "SeriesNameFormat", fn: "SeriesNameFormat", param: valueParam},
+		},
+	},
+	"PyroscopeProfileQuery": {
+		path:  "github.com/perses/plugins/pyroscope/sdk/go/query",
+		alias: "query",
+		fn:    "ProfileQL",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "PyroscopeDatasource"},
+			{field: "Filters", fn: "Filters", param: valueParam},
+			{field: "MaxNodes", fn: "MaxNodes", param: pointerParam},
+			{field: "ProfileType", fn: "ProfileType", param: valueParam},
+			{field: "Service", fn: "Service", param: pointerParam},
+		},
+	},
+	"ScatterChart": {
+		path:  "github.com/perses/plugins/scatterchart/sdk/go",
+		alias: "scatter",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Link", fn: "Link", param: valueParam},
+		},
+	},
+	"SilenceTable": {
+		path:  "github.com/perses/plugins/alertmanager/sdk/go/panel",
+		alias: "panel",
+		fn:    "SilenceTable",
+		options: []sdkOption{
+			{field: "AllowedActions", fn: "SilenceAllowedActions", param: variadicParam},
+			{field: "Columns", fn: "SilenceColumns", param: variadicParam},
+		},
+	},
+	"SplunkLogQuery": {
+		path:  "github.com/perses/plugins/splunk/sdk/go/query/log",
+		alias: "log",
+		fn:    "SplunkLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "SplunkDatasource"},
+			{field: "EarliestTime", fn: "EarliestTime", param: valueParam},
+			{field: "LatestTime", fn: "LatestTime", param: valueParam},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"SplunkTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/splunk/sdk/go/query/time-series",
+		alias: "timeseries",
+		fn:    "SplunkTimeSeriesQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "SplunkDatasource"},
+			{field: "EarliestTime", fn: "EarliestTime", param: valueParam},
+			{field: "LatestTime", fn: "LatestTime", param: valueParam},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"StatChart": {
+		path:  "github.com/perses/plugins/statchart/sdk/go",
+		alias: "stat",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Calculation", fn: "Calculation", param: valueParam},
+			{field: "ColorMode", fn: "WithColorMode", param: valueParam},
+			{field: "Format", fn: "Format", param: pointerParam},
+			{field: "LegendMode", fn: "WithLegendMode", param: valueParam},
+			{field: "Mappings", fn: "Mappings", param: variadicParam},
+			{field: "MetricLabel", fn: "MetricLabel", param: valueParam},
+			{field: "Sparkline", fn: "WithSparkline", param: pointerParam},
+			{field: "Thresholds", fn: "Thresholds", param: pointerParam},
+			{field: "ValueFontSize", fn: "ValueFontSize", param: valueParam},
+		},
+		defaults: []string{"Calculation"},
+	},
+	"StaticListVariable": {
+		path:  "github.com/perses/plugins/staticlistvariable/sdk/go",
+		alias: "staticlist",
+		fn:    "StaticList",
+		options: []sdkOption{
+			{field: "Values", fn: "Values", param: variadicParam},
+		},
+	},
+	"StatusHistoryChart": {
+		path:  "github.com/perses/plugins/statushistorychart/sdk/go",
+		alias: "statushistory",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Legend", fn: "WithLegend", param: pointerParam},
+			{field: "Mappings", fn: "Mappings", param: variadicParam},
+			{field: "Sorting", fn: "WithSorting", param: valueParam},
+		},
+	},
+	"Table": {
+		path:  "github.com/perses/plugins/table/sdk/go",
+		alias: "table",
+		fn:    "Table",
+		options: []sdkOption{
+			{field: "CellSettings", fn: "WithCellSettings", param: valueParam},
+			{field: "ColumnSettings", fn: "WithColumnSettings", param: valueParam},
+			{field: "DefaultColumnHidden", fn: "WithDefaultColumnHidden", param: valueParam},
+			{field: "Density", fn: "WithDensity", param: valueParam},
+			{field: "EnableFiltering", fn: "WithEnableFiltering", param: valueParam},
+			{field: "EnableSorting", fn: "WithEnableSorting", param: valueParam},
+			{field: "Pagination", fn: "WithDefaultPagination", param: valueParam},
+			{field: "Selection", fn: "WithSelection", param: pointerParam},
+			{field: "Transforms", fn: "Transform", param: valueParam},
+		},
+	},
+	"TempoTraceQuery": {
+		path:  "github.com/perses/plugins/tempo/sdk/go/query",
+		alias: "query",
+		fn:    "TraceQL",
+		args: []sdkOption{
+			{field: "Query", fn: "Expr", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "TempoDatasource"},
+			{field: "Limit", fn: "Limit", param: pointerParam},
+			{field: "Query", fn: "Expr", param: valueParam},
+		},
+	},
+	"TimeSeriesChart": {
+		path:  "github.com/perses/plugins/timeserieschart/sdk/go",
+		alias: "timeseries",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Legend", fn: "WithLegend", param: pointerParam},
+			{field: "Thresholds", fn: "Thresholds", param: pointerParam},
+			{field: "Tooltip", fn: "WithTooltip", param: pointerParam},
+			{field: "Visual", fn: "WithVisual", param: pointerParam},
+			{field: "YAxis", fn: "WithYAxis", param: pointerParam},
+		},
+	},
+	"TimeSeriesTable": {
+		path:  "github.com/perses/plugins/timeseriestable/sdk/go",
+		alias: "timeseriestable",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Selection", fn: "WithSelection", param: pointerParam},
+		},
+	},
+	"TraceTable": {
+		path:  "github.com/perses/plugins/tracetable/sdk/go",
+		alias: "tracetable",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Links", fn: "WithLinks", param: pointerParam},
+			{field: "Selection", fn: "WithSelection", param: pointerParam},
+			{field: "Visual", fn: "WithVisual", param: pointerParam},
+		},
+	},
+	"TracingGanttChart": {
+		path:  "github.com/perses/plugins/tracingganttchart/sdk/go",
+		alias: "tracingganttchart",
+		fn:    "Chart",
+		options: []sdkOption{
+			{field: "Links", fn: "WithLinks", param: pointerParam},
+			{field: "Visual", fn: "WithVisual", param: pointerParam},
+		},
+	},
+	"VictoriaLogsFieldNamesVariable": {
+		path:  "github.com/perses/plugins/victorialogs/sdk/go/variable/field-names",
+		alias: "labelnames",
+		fn:    "VictoriaLogsFieldNames",
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "VictoriaLogsDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"VictoriaLogsFieldValuesVariable": {
+		path:  "github.com/perses/plugins/victorialogs/sdk/go/variable/field-values",
+		alias: "labelvalues",
+		fn:    "VictoriaLogsFieldValues",
+		args: []sdkOption{
+			{field: "Field", fn: "Field", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "VictoriaLogsDatasource"},
+			{field: "Field", fn: "Field", param: valueParam},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"VictoriaLogsLogQuery": {
+		path:  "github.com/perses/plugins/victorialogs/sdk/go/query/log",
+		alias: "log",
+		fn:    "VictoriaLogsLogQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "VictoriaLogsDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+	"VictoriaLogsTimeSeriesQuery": {
+		path:  "github.com/perses/plugins/victorialogs/sdk/go/query/time-series",
+		alias: "timeseries",
+		fn:    "VictoriaLogsTimeSeriesQuery",
+		args: []sdkOption{
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+		options: []sdkOption{
+			{field: "Datasource", fn: "Datasource", param: selectorParam, kind: "VictoriaLogsDatasource"},
+			{field: "Query", fn: "Query", param: valueParam},
+		},
+	},
+}
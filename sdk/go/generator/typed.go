@@ -0,0 +1,330 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perses/plugins/sdk/go/registry"
+)
+
+// typedPlugin renders a plugin with a literal of the Go type registered for its kind, e.g.
+// `plugin.Plugin{Kind: "Table", Spec: &table.PluginSpec{...}}`.
+// The spec is decoded with the registry, so the validation of the Go type applies, and the decoded value must marshal
+// back into the same spec: a field the Go type doesn't know would be silently dropped otherwise.
+func (g *generator) typedPlugin(entry registry.Entry, spec any) (string, error) {
+	decoded, err := g.registry.Decode(entry.Kind, spec)
+	if err != nil {
+		return "", err
+	}
+	if roundTripErr := checkRoundTrip(spec, decoded); roundTripErr != nil {
+		return "", fmt.Errorf("the spec cannot be represented by the type %s: %w", entry.SpecType, roundTripErr)
+	}
+	rendered, err := g.renderValue(reflect.ValueOf(decoded), false)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.Plugin{\nKind: %q,\nSpec: %s,\n}", g.use(pluginSpecPath, "plugin"), entry.Kind, rendered), nil
+}
+
+// checkRoundTrip verifies that every field of the spec is kept by the decoded value. The decoded value can only add
+// fields holding a zero value, e.g. the fields of the Go type without `omitempty`.
+func checkRoundTrip(spec any, decoded any) error {
+	var expected, actual any
+	if err := jsonCopy(spec, &expected); err != nil {
+		return err
+	}
+	if err := jsonCopy(decoded, &actual); err != nil {
+		return err
+	}
+	return compareJSON("spec", expected, actual)
+}
+
+func jsonCopy(value any, target *any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func compareJSON(location string, expected any, actual any) error {
+	switch e := expected.(type) {
+	case map[string]any:
+		return compareObjects(location, e, actual)
+	case []any:
+		actualList, isList := actual.([]any)
+		if !isList || len(actualList) != len(e) {
+			return fmt.Errorf("%s is changed into %s", location, marshalString(actual))
+		}
+		for i := range e {
+			if err := compareJSON(fmt.Sprintf("%s[%d]", location, i), e[i], actualList[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nil:
+		if isZeroJSON(actual) {
+			return nil
+		}
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("%s is changed into %s", location, marshalString(actual))
+	}
+	return nil
+}
+
+func compareObjects(location string, expectedObject map[string]any, actual any) error {
+	actualObject, isObject := actual.(map[string]any)
+	if !isObject {
+		return fmt.Errorf("%s is changed into %s", location, marshalString(actual))
+	}
+	for _, key := range sortedKeys(expectedObject) {
+		actualValue, ok := actualObject[key]
+		if !ok {
+			return fmt.Errorf("the field %s.%s is dropped", location, key)
+		}
+		if err := compareJSON(location+"."+key, expectedObject[key], actualValue); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(actualObject) {
+		if _, ok := expectedObject[key]; !ok && !isZeroJSON(actualObject[key]) {
+			return fmt.Errorf("the field %s.%s is added", location, key)
+		}
+	}
+	return nil
+}
+
+func isZeroJSON(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func marshalString(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// renderValue renders a Go value as a Go expression. When the value is held by an interface, inInterface is set and
+// the constants are converted explicitly to their type, so that the dynamic type of the value is kept.
+func (g *generator) renderValue(v reflect.Value, inInterface bool) (string, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		return g.renderPointer(v)
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		return g.renderValue(v.Elem(), true)
+	case reflect.Struct:
+		return g.renderStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "nil", nil
+		}
+		return g.renderList(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		return g.renderMap(v)
+	case reflect.String:
+		return g.convert(v.Type(), strconv.Quote(v.String()), inInterface && v.Type() != reflect.TypeOf(""))
+	case reflect.Bool:
+		return g.convert(v.Type(), strconv.FormatBool(v.Bool()), inInterface && v.Type() != reflect.TypeOf(true))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return g.convert(v.Type(), strconv.FormatInt(v.Int(), 10), inInterface && v.Type() != reflect.TypeOf(0))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return g.convert(v.Type(), strconv.FormatUint(v.Uint(), 10), inInterface)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("the number %v cannot be written as a Go constant", f)
+		}
+		return g.convert(v.Type(), strconv.FormatFloat(f, 'g', -1, 64), inInterface)
+	default:
+		return "", fmt.Errorf("the type %s is not supported by the generator", v.Type())
+	}
+}
+
+// convert wraps the constant in a conversion to its type when needed.
+func (g *generator) convert(t reflect.Type, constant string, needed bool) (string, error) {
+	if !needed {
+		return constant, nil
+	}
+	name, err := g.typeExpr(t)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s(%s)", name, constant), nil
+}
+
+func (g *generator) renderPointer(v reflect.Value) (string, error) {
+	if v.IsNil() {
+		return "nil", nil
+	}
+	elem := v.Elem()
+	rendered, err := g.renderValue(elem, false)
+	if err != nil {
+		return "", err
+	}
+	if elem.Kind() == reflect.Struct {
+		return "&" + rendered, nil
+	}
+	name, err := g.typeExpr(elem.Type())
+	if err != nil {
+		return "", err
+	}
+	g.usePtrHelper = true
+	return fmt.Sprintf("ptr[%s](%s)", name, rendered), nil
+}
+
+func (g *generator) renderStruct(v reflect.Value) (string, error) {
+	name, err := g.typeExpr(v.Type())
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(name + "{\n")
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if v.Field(i).IsZero() {
+			continue
+		}
+		if !f.IsExported() {
+			return "", fmt.Errorf("the unexported field %s of the type %s cannot be set by the generator", f.Name, v.Type())
+		}
+		rendered, renderErr := g.renderValue(v.Field(i), false)
+		if renderErr != nil {
+			return "", fmt.Errorf("%s: %w", f.Name, renderErr)
+		}
+		fmt.Fprintf(&b, "%s: %s,\n", f.Name, rendered)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func (g *generator) renderList(v reflect.Value) (string, error) {
+	name, err := g.typeExpr(v.Type())
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(name + "{")
+	if v.Len() > 0 {
+		b.WriteString("\n")
+	}
+	for i := 0; i < v.Len(); i++ {
+		rendered, renderErr := g.renderElem(v.Index(i))
+		if renderErr != nil {
+			return "", fmt.Errorf("[%d]: %w", i, renderErr)
+		}
+		b.WriteString(rendered + ",\n")
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func (g *generator) renderMap(v reflect.Value) (string, error) {
+	name, err := g.typeExpr(v.Type())
+	if err != nil {
+		return "", err
+	}
+	entries := make([]string, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, keyErr := g.renderValue(iter.Key(), false)
+		if keyErr != nil {
+			return "", keyErr
+		}
+		value, valueErr := g.renderElem(iter.Value())
+		if valueErr != nil {
+			return "", fmt.Errorf("%s: %w", key, valueErr)
+		}
+		entries = append(entries, fmt.Sprintf("%s: %s,\n", key, value))
+	}
+	sort.Strings(entries)
+	return fmt.Sprintf("%s{\n%s}", name, strings.Join(entries, "")), nil
+}
+
+// renderElem renders an element of a list or of a map, eliding its type when it is a struct as gofmt -s does.
+func (g *generator) renderElem(v reflect.Value) (string, error) {
+	rendered, err := g.renderValue(v, false)
+	if err != nil || v.Kind() != reflect.Struct {
+		return rendered, err
+	}
+	name, err := g.typeExpr(v.Type())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(rendered, name), nil
+}
+
+// typeExpr returns the Go expression of a type, importing its package if needed.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		if strings.Contains(t.Name(), "[") {
+			return "", fmt.Errorf("the generic type %s is not supported by the generator", t)
+		}
+		// the package name is the qualifier used by reflect, e.g. `table.PluginSpec`
+		pkgName, _, _ := strings.Cut(t.String(), ".")
+		return g.use(t.PkgPath(), pkgName) + "." + t.Name(), nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), err
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any", nil
+		}
+	}
+	return "", fmt.Errorf("the type %s is not supported by the generator", t)
+}
@@ -0,0 +1,310 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueType renders a JSON value as a Go expression of a given type of the Go SDK.
+// An error is returned when the value cannot be represented by the type, in that case the caller falls back to the raw spec.
+type valueType interface {
+	render(g *generator, value any) (string, error)
+}
+
+// typeRef is a reference to a Go type. When path is empty, the type is a builtin type.
+type typeRef struct {
+	path  string
+	alias string
+	name  string
+}
+
+func builtin(name string) typeRef {
+	return typeRef{name: name}
+}
+
+func (g *generator) typeName(t typeRef) string {
+	if t.path == "" {
+		return t.name
+	}
+	return g.imports.use(t.path, t.alias) + "." + t.name
+}
+
+type stringType struct{}
+
+func (stringType) render(_ *generator, value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, got %T", value)
+	}
+	return strconv.Quote(s), nil
+}
+
+type numberType struct {
+	integer bool
+}
+
+func (t numberType) render(_ *generator, value any) (string, error) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	default:
+		return "", fmt.Errorf("expected a number, got %T", value)
+	}
+	if t.integer && f != math.Trunc(f) {
+		return "", fmt.Errorf("expected an integer, got %v", f)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
+
+type boolType struct{}
+
+func (boolType) render(_ *generator, value any) (string, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return "", fmt.Errorf("expected a boolean, got %T", value)
+	}
+	return strconv.FormatBool(b), nil
+}
+
+// durationType renders a duration string (e.g. "15s") as a time.Duration expression (e.g. 15 * time.Second).
+type durationType struct{}
+
+func (durationType) render(g *generator, value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a duration, got %T", value)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", err
+	}
+	timeAlias := g.imports.use("time", "time")
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{unit: time.Hour, name: "Hour"},
+		{unit: time.Minute, name: "Minute"},
+		{unit: time.Second, name: "Second"},
+		{unit: time.Millisecond, name: "Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			if d == u.unit {
+				return fmt.Sprintf("%s.%s", timeAlias, u.name), nil
+			}
+			return fmt.Sprintf("%d * %s.%s", d/u.unit, timeAlias, u.name), nil
+		}
+	}
+	return fmt.Sprintf("%s.Duration(%d)", timeAlias, d), nil
+}
+
+// selectorType renders a datasource selector as the name of the datasource, as expected by the `Datasource` options.
+type selectorType struct{}
+
+func (selectorType) render(_ *generator, value any) (string, error) {
+	selector, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("expected a datasource selector, got %T", value)
+	}
+	for key := range selector {
+		if key != "kind" && key != "name" {
+			return "", fmt.Errorf("unexpected field %q in datasource selector", key)
+		}
+	}
+	name, _ := selector["name"].(string)
+	return strconv.Quote(name), nil
+}
+
+// ptrType renders a pointer. Pointers to scalar values rely on the generic helper `ptr` added to the generated file.
+type ptrType struct {
+	ref  typeRef
+	elem valueType
+}
+
+func (t ptrType) render(g *generator, value any) (string, error) {
+	elem, err := t.elem.render(g, value)
+	if err != nil {
+		return "", err
+	}
+	if _, isStruct := t.elem.(structType); isStruct {
+		return "&" + elem, nil
+	}
+	g.usePtrHelper = true
+	return fmt.Sprintf("ptr[%s](%s)", g.typeName(t.ref), elem), nil
+}
+
+type listType struct {
+	ref  typeRef
+	elem valueType
+}
+
+func (t listType) render(g *generator, value any) (string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return "", fmt.Errorf("expected a list, got %T", value)
+	}
+	rendered, err := renderItems(g, t.elem, items)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("[]%s{%s}", g.typeName(t.ref), rendered), nil
+}
+
+type mapType struct {
+	ref  typeRef
+	elem valueType
+}
+
+func (t mapType) render(g *generator, value any) (string, error) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("expected an object, got %T", value)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "map[string]%s{\n", g.typeName(t.ref))
+	for _, key := range sortedKeys(m) {
+		v, err := t.elem.render(g, m[key])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(key), v)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+type field struct {
+	json   string
+	goName string
+	typ    valueType
+}
+
+type structType struct {
+	ref    typeRef
+	fields []field
+}
+
+func (t structType) render(g *generator, value any) (string, error) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("expected an object, got %T", value)
+	}
+	fields := make(map[string]field, len(t.fields))
+	for _, f := range t.fields {
+		fields[f.json] = f
+	}
+	var b strings.Builder
+	b.WriteString(g.typeName(t.ref) + "{\n")
+	for _, key := range sortedKeys(m) {
+		f, known := fields[key]
+		if !known {
+			return "", fmt.Errorf("unknown field %q", key)
+		}
+		v, err := f.typ.render(g, m[key])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		fmt.Fprintf(&b, "%s: %s,\n", f.goName, v)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// mappingType renders a value mapping (stat.Mapping), whose type of spec depends on the kind of the mapping.
+type mappingType struct{}
+
+func (mappingType) render(g *generator, value any) (string, error) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("expected an object, got %T", value)
+	}
+	for key := range m {
+		if key != "kind" && key != "spec" {
+			return "", fmt.Errorf("unknown field %q", key)
+		}
+	}
+	kind, _ := m["kind"].(string)
+	specType, ok := mappingSpecTypes[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown kind of mapping %q", kind)
+	}
+	spec, err := ptrType{elem: specType}.render(g, m["spec"])
+	if err != nil {
+		return "", fmt.Errorf("spec: %w", err)
+	}
+	return fmt.Sprintf("%s{\nKind: %q,\nSpec: %s,\n}", g.typeName(statChartType("Mapping")), kind, spec), nil
+}
+
+// rawType renders any JSON value with the generic Go types (map[string]any, []any, ...).
+type rawType struct{}
+
+func (rawType) render(g *generator, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case []any:
+		items, err := renderItems(g, rawType{}, v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[]any{%s}", items), nil
+	case map[string]any:
+		return mapType{ref: builtin("any"), elem: rawType{}}.render(g, v)
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+func renderItems(g *generator, elem valueType, items []any) (string, error) {
+	if len(items) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, item := range items {
+		v, err := elem.render(g, item)
+		if err != nil {
+			return "", fmt.Errorf("[%d]: %w", i, err)
+		}
+		b.WriteString(v + ",\n")
+	}
+	return b.String(), nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
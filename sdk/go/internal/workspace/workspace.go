@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workspace creates Go workspaces made of the modules of a clone of this repository. The root module cannot
+// depend on the plugin modules, so a program using the Go SDK of every plugin (e.g. to fill a registry) is run in such
+// a workspace.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/perses/plugins/scripts/npm"
+)
+
+const rootModulePath = "github.com/perses/plugins"
+
+var goVersionPattern = regexp.MustCompile(`(?m)^go (\S+)$`)
+
+// Module is a plugin module registering the plugins of its Go SDK in a registry.go file.
+type Module struct {
+	Dir  string
+	Path string
+}
+
+// PluginModules returns the plugin modules of the repository cloned in root.
+func PluginModules(root string) ([]Module, error) {
+	rootPath, err := ReadModulePath(filepath.Join(root, "go.mod"))
+	if err != nil || rootPath != rootModulePath {
+		return nil, fmt.Errorf("%s is not a clone of %s", root, rootModulePath)
+	}
+	workspaces, err := npm.GetWorkspaces(root)
+	if err != nil {
+		return nil, err
+	}
+	var modules []Module
+	for _, workspace := range workspaces {
+		dir := filepath.Join(root, workspace)
+		if _, statErr := os.Stat(filepath.Join(dir, "registry.go")); statErr != nil {
+			continue
+		}
+		modulePath, readErr := ReadModulePath(filepath.Join(dir, "go.mod"))
+		if readErr != nil {
+			return nil, readErr
+		}
+		modules = append(modules, Module{Dir: dir, Path: modulePath})
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no plugin module found in %s", root)
+	}
+	return modules, nil
+}
+
+// Create makes dir the main module of a Go workspace using the root module of the repository cloned in root and the
+// given plugin modules. The Go version of the workspace is the one of the root module.
+func Create(dir string, root string, modules []Module) error {
+	rootGoMod, err := os.ReadFile(filepath.Join(root, "go.mod")) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	goVersion := goVersionPattern.FindSubmatch(rootGoMod)
+	if goVersion == nil {
+		return fmt.Errorf("no go directive in %s", filepath.Join(root, "go.mod"))
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	var goWork strings.Builder
+	fmt.Fprintf(&goWork, "go %s\n\nuse (\n\t.\n\t%s\n", goVersion[1], absRoot)
+	for _, module := range modules {
+		moduleDir, absErr := filepath.Abs(module.Dir)
+		if absErr != nil {
+			return absErr
+		}
+		fmt.Fprintf(&goWork, "\t%s\n", moduleDir)
+	}
+	goWork.WriteString(")\n")
+	if writeErr := os.WriteFile(filepath.Join(dir, "go.work"), []byte(goWork.String()), 0600); writeErr != nil {
+		return writeErr
+	}
+	return os.WriteFile(filepath.Join(dir, "go.mod"), []byte(fmt.Sprintf("module example.com/generated\n\ngo %s\n", goVersion[1])), 0600)
+}
+
+// Command returns the go command running in the workspace created in dir.
+func Command(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	// -mod=mod is not allowed in workspace mode
+	cmd.Env = append(os.Environ(), "GOWORK="+filepath.Join(dir, "go.work"), "GOFLAGS=")
+	return cmd
+}
+
+// ReadModulePath returns the module path declared in the given go.mod file.
+func ReadModulePath(goModPath string) (string, error) {
+	data, err := os.ReadFile(goModPath) //nolint: gosec
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return modulePath, nil
+		}
+	}
+	return "", fmt.Errorf("no module path found in %s", goModPath)
+}
@@ -0,0 +1,185 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update regenerates the expected output of the migration tests:
+//
+//	go test ./sdk/go/migrate -run TestMigrationFixtures -update
+var update = flag.Bool("update", false, "regenerate the expected.json files of the migration tests")
+
+// offlineEnv skips the plugins whose CUE dependencies are not in the CUE module cache and cannot be downloaded:
+//
+//	PERSES_TEST_OFFLINE=true go test ./sdk/go/migrate
+const offlineEnv = "PERSES_TEST_OFFLINE"
+
+const (
+	repositoryRoot = "../../.."
+	testsDir       = "tests"
+	inputFile      = "input.json"
+	expectedFile   = "expected.json"
+)
+
+// TestMigrationFixtures runs every migration test of the plugins: each directory `<migration>/tests/<name>` holds the
+// Grafana object to migrate (input.json) and the plugin expected from the migration (expected.json).
+// The CUE dependencies are read from the CUE module cache, so the tests run offline once they have been downloaded.
+// A plugin whose dependencies cannot be fetched fails, unless PERSES_TEST_OFFLINE is set.
+func TestMigrationFixtures(t *testing.T) {
+	for _, workspace := range npm.MustGetWorkspaces(repositoryRoot) {
+		pluginDir := filepath.Join(repositoryRoot, workspace)
+		if _, err := os.Stat(filepath.Join(pluginDir, moduleFile)); err != nil {
+			continue
+		}
+		t.Run(workspace, func(t *testing.T) {
+			m, err := New(os.DirFS(pluginDir))
+			if err != nil && strings.Contains(err.Error(), "cannot fetch") && os.Getenv(offlineEnv) == "true" {
+				t.Skipf("%s is set and the CUE dependencies of the plugin are not available: %s", offlineEnv, err)
+			}
+			require.NoError(t, err)
+			for _, migrations := range [][]*migration{m.panels, m.queries, m.variables} {
+				for _, mig := range migrations {
+					runFixtures(t, m, mig, filepath.Join(pluginDir, filepath.FromSlash(mig.dir), testsDir))
+				}
+			}
+		})
+	}
+}
+
+// TestMigrationWarnings checks the warnings of the TimeSeriesChart migration, whose branches read different options
+// depending on the type of the Grafana panel: the options read by a branch that is not taken must be reported.
+func TestMigrationWarnings(t *testing.T) {
+	m, err := New(os.DirFS(filepath.Join(repositoryRoot, "timeserieschart")))
+	if err != nil && strings.Contains(err.Error(), "cannot fetch") && os.Getenv(offlineEnv) == "true" {
+		t.Skipf("%s is set and the CUE dependencies of the plugin are not available: %s", offlineEnv, err)
+	}
+	require.NoError(t, err)
+	input, err := os.ReadFile(filepath.Join(repositoryRoot, "timeserieschart", "schemas", "migrate", testsDir, "graph-legend-right-table", inputFile))
+	require.NoError(t, err)
+	legendWarnings := []string{"legend.alignAsTable", "legend.avg", "legend.current", "legend.max", "legend.rightSide"}
+
+	testSuite := []struct {
+		title      string
+		input      string
+		warned     bool
+		legendMode string
+	}{
+		{
+			title:      "graph panel: the legend is migrated",
+			input:      string(input),
+			legendMode: "table",
+		},
+		{
+			title:  "timeseries panel: the legend of the graph panel is dropped",
+			input:  strings.Replace(string(input), `"type": "graph"`, `"type": "timeseries"`, 1),
+			warned: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := m.MigratePanel([]byte(test.input))
+			require.NoError(t, err)
+			var spec struct {
+				Legend *struct {
+					Mode string `json:"mode"`
+				} `json:"legend"`
+			}
+			require.NoError(t, json.Unmarshal(result.Spec, &spec))
+			if test.legendMode == "" {
+				assert.Nil(t, spec.Legend)
+			} else if assert.NotNil(t, spec.Legend) {
+				assert.Equal(t, test.legendMode, spec.Legend.Mode)
+			}
+			warned := make(map[string]bool)
+			for _, w := range result.Warnings {
+				warned[w.Path] = true
+			}
+			for _, path := range legendWarnings {
+				assert.Equal(t, test.warned, warned[path], path)
+			}
+		})
+	}
+}
+
+func runFixtures(t *testing.T, m *Migrator, mig *migration, dir string) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return
+	}
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		testDir := filepath.Join(dir, entry.Name())
+		t.Run(mig.kind+"/"+entry.Name(), func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(testDir, inputFile))
+			require.NoError(t, err)
+			var grafanaObject map[string]any
+			require.NoError(t, json.Unmarshal(data, &grafanaObject))
+			spec, _, ok := mig.run(m.ctx.CompileBytes(data), grafanaObject)
+			require.True(t, ok, "the migration doesn't apply to %s", inputFile)
+
+			actual, err := formatPlugin(mig.kind, spec)
+			require.NoError(t, err)
+			expectedPath := filepath.Join(testDir, expectedFile)
+			expected, err := os.ReadFile(expectedPath)
+			if *update {
+				if err != nil || !jsonEqual(expected, actual) {
+					require.NoError(t, os.WriteFile(expectedPath, actual, 0644))
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+// formatPlugin renders the plugin like the expected.json files: indented with 2 spaces and the keys sorted.
+func formatPlugin(kind string, spec json.RawMessage) ([]byte, error) {
+	var specValue any
+	if err := json.Unmarshal(spec, &specValue); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(map[string]any{"kind": kind, "spec": specValue}); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func jsonEqual(a []byte, b []byte) bool {
+	var aValue, bValue any
+	if json.Unmarshal(a, &aValue) != nil || json.Unmarshal(b, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
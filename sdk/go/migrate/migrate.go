@@ -0,0 +1,284 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate converts Grafana panels, queries and variables into Perses plugins from Go.
+//
+// The conversion is not re-implemented: the `migrate.cue` files of the plugins are evaluated with the CUE Go evaluator,
+// exactly like the Perses server does. Every plugin module exposes its CUE module through the `embed.FS` named
+// `Schemas`, located at the root of the module (e.g. `github.com/perses/plugins/timeserieschart`).
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/parser"
+)
+
+const (
+	moduleFile     = "cue.mod/module.cue"
+	schemasDir     = "schemas"
+	migratePackage = "migrate"
+	virtualPrefix  = "/perses-plugin"
+)
+
+// ErrNoMigration is returned when no migration of the loaded plugins matches the Grafana object.
+var ErrNoMigration = errors.New("no migration matches the Grafana object")
+
+// Warning reports a Grafana option that has been dropped by the migration.
+type Warning struct {
+	// Path is the location of the option in the Grafana object, e.g. `options.legend.sortBy`.
+	Path    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Message)
+}
+
+// Result is the plugin produced by a migration.
+type Result struct {
+	Kind     string
+	Spec     json.RawMessage
+	Warnings []Warning
+}
+
+// Migration is the plugin produced by a migration, with its spec decoded into the Go type of the plugin SDK.
+type Migration[T any] struct {
+	Kind     string
+	Spec     *T
+	Warnings []Warning
+}
+
+// Migrator runs the migrations of a set of plugins.
+// It is safe for concurrent use.
+type Migrator struct {
+	mutex     sync.Mutex
+	ctx       *cue.Context
+	panels    []*migration
+	queries   []*migration
+	variables []*migration
+}
+
+// New loads the migrations of every given plugin module.
+// Each file system must contain the CUE module of a plugin: `cue.mod/module.cue` and the `schemas` directory.
+// The CUE dependencies of the migrations are resolved through the CUE registry (see `CUE_REGISTRY` and `CUE_CACHE_DIR`).
+func New(pluginSchemas ...fs.FS) (*Migrator, error) {
+	m := &Migrator{
+		ctx: cuecontext.New(),
+	}
+	for i, fsys := range pluginSchemas {
+		if err := m.load(fmt.Sprintf("%s-%d", virtualPrefix, i), fsys); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Kinds returns the sorted list of the plugin kinds produced by the loaded migrations.
+func (m *Migrator) Kinds() []string {
+	var kinds []string
+	for _, migrations := range [][]*migration{m.panels, m.queries, m.variables} {
+		for _, mig := range migrations {
+			kinds = append(kinds, mig.kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// MigratePanel converts a Grafana panel (the JSON of one item of `panels` in a Grafana dashboard) into the plugin
+// of a Perses panel. The migration is selected with the type of the Grafana panel.
+// The queries of the panel are not migrated, see MigrateQuery.
+func (m *Migrator) MigratePanel(grafanaPanel []byte) (*Result, error) {
+	return m.migrate(m.panels, grafanaPanel)
+}
+
+// MigrateQuery converts a Grafana query (the JSON of one item of `targets` in a Grafana panel) into a query plugin.
+func (m *Migrator) MigrateQuery(grafanaTarget []byte) (*Result, error) {
+	return m.migrate(m.queries, grafanaTarget)
+}
+
+// MigrateVariable converts a Grafana variable (the JSON of one item of `templating.list` in a Grafana dashboard) into
+// the plugin of a Perses list variable.
+func (m *Migrator) MigrateVariable(grafanaVariable []byte) (*Result, error) {
+	return m.migrate(m.variables, grafanaVariable)
+}
+
+// Panel migrates a Grafana panel and decodes the result into the Go type of the plugin spec.
+func Panel[T any](m *Migrator, grafanaPanel []byte) (*Migration[T], error) {
+	return decode[T](m.MigratePanel(grafanaPanel))
+}
+
+// Query migrates a Grafana query and decodes the result into the Go type of the plugin spec.
+func Query[T any](m *Migrator, grafanaTarget []byte) (*Migration[T], error) {
+	return decode[T](m.MigrateQuery(grafanaTarget))
+}
+
+// Variable migrates a Grafana variable and decodes the result into the Go type of the plugin spec.
+func Variable[T any](m *Migrator, grafanaVariable []byte) (*Migration[T], error) {
+	return decode[T](m.MigrateVariable(grafanaVariable))
+}
+
+func decode[T any](result *Result, err error) (*Migration[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	spec := new(T)
+	if unmarshalErr := json.Unmarshal(result.Spec, spec); unmarshalErr != nil {
+		return nil, fmt.Errorf("unable to decode the spec of the plugin %q: %w", result.Kind, unmarshalErr)
+	}
+	return &Migration[T]{Kind: result.Kind, Spec: spec, Warnings: result.Warnings}, nil
+}
+
+func (m *Migrator) migrate(migrations []*migration, data []byte) (*Result, error) {
+	var grafanaObject map[string]any
+	if err := json.Unmarshal(data, &grafanaObject); err != nil {
+		return nil, fmt.Errorf("unable to decode the Grafana object: %w", err)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	input := m.ctx.CompileBytes(data)
+	if input.Err() != nil {
+		return nil, fmt.Errorf("unable to compile the Grafana object: %w", input.Err())
+	}
+	for _, mig := range migrations {
+		spec, evaluated, ok := mig.run(input, grafanaObject)
+		if !ok {
+			continue
+		}
+		return &Result{
+			Kind:     mig.kind,
+			Spec:     spec,
+			Warnings: mig.references.read(evaluated).dropped(grafanaObject, mig.input.ignored),
+		}, nil
+	}
+	return nil, ErrNoMigration
+}
+
+// load reads the CUE module contained in fsys and builds every migration of the plugin.
+// The files are given to CUE through an overlay mounted on root, so nothing needs to be written on disk.
+func (m *Migrator) load(root string, fsys fs.FS) error {
+	if _, err := fs.Stat(fsys, moduleFile); err != nil {
+		return fmt.Errorf("invalid plugin schemas: %w", err)
+	}
+	overlay := make(map[string]load.Source)
+	migrateDirs := make(map[string][]sourceFile)
+	err := fs.WalkDir(fsys, ".", func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(currentPath) != ".cue" {
+			return nil
+		}
+		data, readErr := fs.ReadFile(fsys, currentPath)
+		if readErr != nil {
+			return readErr
+		}
+		overlay[path.Join(root, currentPath)] = load.FromBytes(data)
+		if strings.HasPrefix(currentPath, schemasDir+"/") && strings.Contains(string(data), "package "+migratePackage) {
+			dir := path.Dir(currentPath)
+			migrateDirs[dir] = append(migrateDirs[dir], sourceFile{path: path.Join(root, currentPath), data: data})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read the plugin schemas: %w", err)
+	}
+	dirs := make([]string, 0, len(migrateDirs))
+	for dir := range migrateDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		mig, loadErr := m.loadMigration(path.Join(root, dir), overlay, migrateDirs[dir])
+		if loadErr != nil {
+			return loadErr
+		}
+		mig.dir = dir
+		switch mig.input.definition {
+		case panelInput.definition:
+			m.panels = append(m.panels, mig)
+		case queryInput.definition:
+			m.queries = append(m.queries, mig)
+		case variableInput.definition:
+			m.variables = append(m.variables, mig)
+		}
+	}
+	return nil
+}
+
+// sourceFile is a CUE file of a migration, located at path in the overlay.
+type sourceFile struct {
+	path string
+	data []byte
+}
+
+func (m *Migrator) loadMigration(dir string, overlay map[string]load.Source, files []sourceFile) (*migration, error) {
+	// The files are instrumented before being built, so that the references of the migration know their branches.
+	// The references are collected for every input, the actual input being known once the migration is built.
+	refs := make(map[string]references)
+	counter := 0
+	for _, file := range files {
+		parsed, parseErr := parser.ParseFile(file.path, file.data, parser.ParseComments)
+		if parseErr != nil {
+			return nil, fmt.Errorf("unable to parse the migration at %s: %w", dir, parseErr)
+		}
+		fileRefs := instrument(parsed, []string{panelInput.definition, queryInput.definition, variableInput.definition}, &counter)
+		for definition, definitionRefs := range fileRefs {
+			refs[definition] = append(refs[definition], definitionRefs...)
+		}
+		instrumented, formatErr := format.Node(parsed)
+		if formatErr != nil {
+			return nil, fmt.Errorf("unable to instrument the migration at %s: %w", dir, formatErr)
+		}
+		overlay[file.path] = load.FromBytes(instrumented)
+	}
+	buildInstances := load.Instances([]string{}, &load.Config{Dir: dir, Package: migratePackage, Overlay: overlay})
+	if len(buildInstances) != 1 {
+		return nil, fmt.Errorf("the number of build instances in %s is != 1", dir)
+	}
+	if buildInstances[0].Err != nil {
+		return nil, fmt.Errorf("failed to load migration from %q: %w", dir, buildInstances[0].Err)
+	}
+	value := m.ctx.BuildInstance(buildInstances[0])
+	if value.Err() != nil {
+		return nil, fmt.Errorf("failed to build migration from %q: %w", dir, value.Err())
+	}
+	kind, err := value.LookupPath(cue.ParsePath("kind")).String()
+	if err != nil {
+		return nil, fmt.Errorf("invalid migration at %s: unable to read the `kind` field: %w", dir, err)
+	}
+	mig := &migration{kind: kind, value: value}
+	for _, in := range []input{panelInput, queryInput, variableInput} {
+		if value.LookupPath(in.path()).Exists() {
+			mig.input = in
+			break
+		}
+	}
+	if mig.input.definition == "" {
+		return nil, fmt.Errorf("invalid migration at %s: none of #panel, #target or #grafanaVar is defined", dir)
+	}
+	mig.references = refs[mig.input.definition]
+	return mig, nil
+}
@@ -0,0 +1,162 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchemas = fstest.MapFS{
+	"cue.mod/module.cue": {Data: []byte(`module: "github.com/perses/plugins/test@v0"
+language: version: "v0.15.1"
+`)},
+	"schemas/test-chart/chart.cue": {Data: []byte(`package model
+
+kind: "TestChart"
+spec: close({
+	legend?: position: "bottom" | "right"
+	unit?: string
+})
+`)},
+	"schemas/test-chart/migrate/migrate.cue": {Data: []byte(`package migrate
+
+#grafanaType: "timeseries" | "graph"
+#panel:       _
+
+kind: "TestChart"
+spec: {
+	if #panel.options.legend.placement != _|_ {
+		legend: position: #panel.options.legend.placement
+	}
+	if #panel.fieldConfig.defaults.unit != _|_ {
+		unit: #panel.fieldConfig.defaults.unit
+	}
+	if #panel.fieldConfig.defaults.custom != _|_ {
+		#custom: #panel.fieldConfig.defaults.custom
+	}
+}
+`)},
+	"schemas/test-query/migrate/migrate.cue": {Data: []byte(`package migrate
+
+#target: {
+	expr: string
+	...
+}
+
+kind: "TestQuery"
+spec: {
+	query: #target.expr
+	if #target["legendFormat"] != _|_ {
+		seriesNameFormat: #target["legendFormat"]
+	}
+}
+`)},
+	"schemas/test-variable/migrate/migrate.cue": {Data: []byte(`package migrate
+
+#grafanaVar: {
+	type:  "custom"
+	query: string
+	...
+}
+
+kind: "TestVariable"
+spec: values: [#grafanaVar.query]
+`)},
+}
+
+type chartSpec struct {
+	Legend *struct {
+		Position string `json:"position"`
+	} `json:"legend,omitempty"`
+	Unit string `json:"unit,omitempty"`
+}
+
+func newTestMigrator(t *testing.T) *Migrator {
+	m, err := New(testSchemas)
+	require.NoError(t, err)
+	return m
+}
+
+func TestNew(t *testing.T) {
+	m := newTestMigrator(t)
+	assert.Equal(t, []string{"TestChart", "TestQuery", "TestVariable"}, m.Kinds())
+
+	_, err := New(fstest.MapFS{})
+	assert.Error(t, err)
+}
+
+func TestMigratePanel(t *testing.T) {
+	m := newTestMigrator(t)
+	result, err := m.MigratePanel([]byte(`{
+  "id": 2,
+  "type": "timeseries",
+  "title": "CPU",
+  "targets": [{"expr": "up"}],
+  "options": {"legend": {"placement": "right", "sortBy": "Name", "calcs": []}},
+  "fieldConfig": {"defaults": {"unit": "percent", "min": 0, "custom": {"lineWidth": 2}}, "overrides": []}
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, "TestChart", result.Kind)
+	assert.JSONEq(t, `{"legend": {"position": "right"}, "unit": "percent"}`, string(result.Spec))
+	// custom is read as a whole, so none of its fields is reported
+	assert.Equal(t, []Warning{
+		{Path: "fieldConfig.defaults.min", Message: "the option is not supported by the migration and has been dropped"},
+		{Path: "options.legend.sortBy", Message: "the option is not supported by the migration and has been dropped"},
+	}, result.Warnings)
+
+	_, err = m.MigratePanel([]byte(`{"type": "piechart"}`))
+	assert.True(t, errors.Is(err, ErrNoMigration))
+}
+
+func TestMigrateQuery(t *testing.T) {
+	m := newTestMigrator(t)
+	result, err := m.MigrateQuery([]byte(`{"refId": "A", "expr": "up", "legendFormat": "{{job}}", "exemplar": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, "TestQuery", result.Kind)
+	assert.JSONEq(t, `{"query": "up", "seriesNameFormat": "{{job}}"}`, string(result.Spec))
+	assert.Equal(t, []Warning{{Path: "exemplar", Message: "the option is not supported by the migration and has been dropped"}}, result.Warnings)
+
+	_, err = m.MigrateQuery([]byte(`{"refId": "A", "query": "{job=\"node\"}"}`))
+	assert.True(t, errors.Is(err, ErrNoMigration))
+}
+
+func TestMigrateVariable(t *testing.T) {
+	m := newTestMigrator(t)
+	result, err := m.MigrateVariable([]byte(`{"name": "env", "type": "custom", "query": "prod"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "TestVariable", result.Kind)
+	assert.JSONEq(t, `{"values": ["prod"]}`, string(result.Spec))
+	assert.Empty(t, result.Warnings)
+
+	_, err = m.MigrateVariable([]byte(`{"name": "env", "type": "query", "query": "prod"}`))
+	assert.True(t, errors.Is(err, ErrNoMigration))
+}
+
+func TestPanel(t *testing.T) {
+	m := newTestMigrator(t)
+	migration, err := Panel[chartSpec](m, []byte(`{"type": "graph", "fieldConfig": {"defaults": {"unit": "bytes"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "TestChart", migration.Kind)
+	assert.Equal(t, &chartSpec{Unit: "bytes"}, migration.Spec)
+
+	_, err = Panel[chartSpec](m, []byte(`not json`))
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+}
@@ -0,0 +1,342 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+)
+
+// input describes the Grafana object given to a migration.
+type input struct {
+	// definition is the CUE definition receiving the Grafana object in the migrate.cue files.
+	definition string
+	// ignored are the fields of the Grafana object that are migrated by Perses itself and not by the plugins.
+	ignored map[string]bool
+}
+
+func (i input) path() cue.Path {
+	return cue.MakePath(cue.Def(i.definition))
+}
+
+func fieldSet(fields ...string) map[string]bool {
+	result := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		result[f] = true
+	}
+	return result
+}
+
+var (
+	panelInput = input{
+		definition: "#panel",
+		ignored:    fieldSet("id", "type", "title", "description", "gridPos", "targets", "datasource", "links", "pluginVersion", "libraryPanel", "repeat", "repeatDirection", "maxPerRow", "collapsed", "panels"),
+	}
+	queryInput = input{
+		definition: "#target",
+		ignored:    fieldSet("refId", "key"),
+	}
+	variableInput = input{
+		definition: "#grafanaVar",
+		ignored:    fieldSet("id", "name", "label", "description", "hide", "type", "current", "options", "multi", "includeAll", "allValue", "regex", "sort", "skipUrlSync", "refresh", "datasource", "definition", "error", "index", "global", "rootStateKey", "state"),
+	}
+)
+
+type migration struct {
+	// dir is the location of the migration in the plugin module, e.g. `schemas/migrate`.
+	dir        string
+	kind       string
+	input      input
+	value      cue.Value
+	references references
+}
+
+// run evaluates the migration with the given Grafana object, and returns the spec of the plugin along with the whole
+// evaluated migration. It returns false when the migration doesn't apply to the object.
+func (mig *migration) run(grafanaValue cue.Value, grafanaObject map[string]any) (json.RawMessage, cue.Value, bool) {
+	if mig.input.definition == panelInput.definition {
+		// the migration of a panel is selected with the type of the Grafana panel
+		grafanaType, _ := grafanaObject["type"].(string)
+		typeValue := mig.value.LookupPath(cue.MakePath(cue.Def("#grafanaType")))
+		if typeValue.Unify(mig.value.Context().Encode(grafanaType)).Err() != nil {
+			return nil, cue.Value{}, false
+		}
+	}
+	result := mig.value.FillPath(mig.input.path(), grafanaValue)
+	if result.LookupPath(mig.input.path()).Validate() != nil {
+		return nil, cue.Value{}, false
+	}
+	kind, err := result.LookupPath(cue.ParsePath("kind")).String()
+	if err != nil || kind != mig.kind {
+		return nil, cue.Value{}, false
+	}
+	spec := result.LookupPath(cue.ParsePath("spec"))
+	if !spec.Exists() || spec.Validate(cue.Concrete(true)) != nil {
+		return nil, cue.Value{}, false
+	}
+	data, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, cue.Value{}, false
+	}
+	return data, result, true
+}
+
+// wildcard matches any field of an object, used when a migration reads a field with a dynamic name.
+const wildcard = "*"
+
+// branchPrefix is the prefix of the definitions added to the body of the conditional structs of a migration
+// (`if cond {...}`): a definition is only present in the evaluated migration when its branch is taken.
+const branchPrefix = "#migrateBranch"
+
+// reference is a path of the Grafana object read by a migration, e.g. `options.legend.calcs`.
+type reference struct {
+	path []string
+	// branches are the locations of the markers of the conditional structs enclosing the expression reading the path.
+	// The path is only read when every marker is present in the evaluated migration.
+	branches []cue.Path
+}
+
+type references []reference
+
+// read returns the paths read by the evaluation of the migration, i.e. the references whose branches are taken.
+func (r references) read(evaluated cue.Value) fieldPaths {
+	var result fieldPaths
+	for _, ref := range r {
+		taken := true
+		for _, branch := range ref.branches {
+			if !evaluated.LookupPath(branch).Exists() {
+				taken = false
+				break
+			}
+		}
+		if taken {
+			result = append(result, ref.path)
+		}
+	}
+	return result
+}
+
+// instrument returns, for each given definition, the paths of every selector rooted at it (e.g. `#panel.options.legend`),
+// the parents of a path being returned as well. A marker is added to the body of every conditional struct of the file,
+// so that each reference knows the branches it depends on.
+// The conditions of a comprehension only depend on the enclosing branches. When the location of a conditional struct
+// cannot be computed (e.g. in a list), no marker is added and its references only depend on the enclosing branches.
+func instrument(file *ast.File, definitions []string, counter *int) map[string]references {
+	type frame struct {
+		comprehension *ast.Comprehension
+		marker        *cue.Path
+		inBody        bool
+	}
+	var (
+		result      = make(map[string]references)
+		labels      []cue.Selector
+		validLabels []bool
+		frames      []*frame
+		conditional = make(map[*ast.Comprehension]bool)
+		markers     = make(map[*ast.StructLit]string)
+	)
+	markDecls := func(decls []ast.Decl) {
+		for _, decl := range decls {
+			if c, ok := decl.(*ast.Comprehension); ok {
+				if _, isStruct := c.Value.(*ast.StructLit); isStruct {
+					conditional[c] = true
+				}
+			}
+		}
+	}
+	branches := func() []cue.Path {
+		var paths []cue.Path
+		for _, f := range frames {
+			if f.inBody && f.marker != nil {
+				paths = append(paths, *f.marker)
+			}
+		}
+		return paths
+	}
+	ast.Walk(file, func(node ast.Node) bool {
+		if len(frames) > 0 && node == frames[len(frames)-1].comprehension.Value {
+			frames[len(frames)-1].inBody = true
+		}
+		switch n := node.(type) {
+		case *ast.File:
+			markDecls(n.Decls)
+		case *ast.StructLit:
+			markDecls(n.Elts)
+		case *ast.ListLit:
+			// the location of the items of a list is not tracked
+			labels = append(labels, cue.Selector{})
+			validLabels = append(validLabels, false)
+		case *ast.Field:
+			selector, valid := fieldSelector(n)
+			labels = append(labels, selector)
+			validLabels = append(validLabels, valid)
+		case *ast.Comprehension:
+			f := &frame{comprehension: n}
+			if isValidLocation(validLabels) && conditional[n] {
+				name := fmt.Sprintf("%s%d", branchPrefix, *counter)
+				*counter++
+				marker := cue.MakePath(append(append([]cue.Selector{}, labels...), cue.Def(name))...)
+				f.marker = &marker
+				markers[n.Value.(*ast.StructLit)] = name
+			}
+			frames = append(frames, f)
+		}
+		for _, definition := range definitions {
+			if chain, ok := referenceChain(node, definition); ok && len(chain) > 0 {
+				result[definition] = append(result[definition], reference{path: chain, branches: branches()})
+			}
+		}
+		return true
+	}, func(node ast.Node) {
+		switch node.(type) {
+		case *ast.ListLit, *ast.Field:
+			labels = labels[:len(labels)-1]
+			validLabels = validLabels[:len(validLabels)-1]
+		case *ast.Comprehension:
+			frames = frames[:len(frames)-1]
+		}
+	})
+	for body, name := range markers {
+		body.Elts = append(body.Elts, &ast.Field{Label: ast.NewIdent(name), Value: ast.NewBool(true)})
+	}
+	return result
+}
+
+func referenceChain(node ast.Node, definition string) ([]string, bool) {
+	switch n := node.(type) {
+	case *ast.Ident:
+		return nil, n.Name == definition
+	case *ast.SelectorExpr:
+		chain, ok := referenceChain(n.X, definition)
+		if !ok {
+			return nil, false
+		}
+		name, _, err := ast.LabelName(n.Sel)
+		if err != nil {
+			name = wildcard
+		}
+		return append(chain, name), true
+	case *ast.IndexExpr:
+		chain, ok := referenceChain(n.X, definition)
+		if !ok {
+			return nil, false
+		}
+		if lit, isLit := n.Index.(*ast.BasicLit); isLit {
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				return append(chain, name), true
+			}
+		}
+		return append(chain, wildcard), true
+	}
+	return nil, false
+}
+
+func fieldSelector(field *ast.Field) (cue.Selector, bool) {
+	name, _, err := ast.LabelName(field.Label)
+	if err != nil || strings.HasPrefix(name, "_") {
+		// dynamic labels, patterns and hidden fields
+		return cue.Selector{}, false
+	}
+	if strings.HasPrefix(name, "#") {
+		return cue.Def(name), true
+	}
+	return cue.Str(name), true
+}
+
+func isValidLocation(validLabels []bool) bool {
+	for _, valid := range validLabels {
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldPaths are paths of the Grafana object, e.g. `options.legend.calcs`.
+type fieldPaths [][]string
+
+// consumes tells whether the field at the given path is used by the migration.
+// A field is used when the migration reads it, or when the migration reads one of its parents as a whole.
+func (r fieldPaths) consumes(fieldPath []string) bool {
+	for _, ref := range r {
+		if len(ref) > len(fieldPath) || !matchPrefix(ref, fieldPath) {
+			continue
+		}
+		if len(ref) == len(fieldPath) || !r.hasChild(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r fieldPaths) hasChild(parent []string) bool {
+	for _, ref := range r {
+		if len(ref) > len(parent) && matchPrefix(parent, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPrefix(prefix []string, fieldPath []string) bool {
+	for i, segment := range prefix {
+		if segment != wildcard && fieldPath[i] != wildcard && segment != fieldPath[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// dropped returns a warning for every option of the Grafana object that is not used by the migration.
+// Lists are considered as a single option. Empty values are ignored, as they don't carry any setting.
+func (r fieldPaths) dropped(grafanaObject map[string]any, ignored map[string]bool) []Warning {
+	var warnings []Warning
+	var walk func(fieldPath []string, value any)
+	walk = func(fieldPath []string, value any) {
+		switch v := value.(type) {
+		case nil:
+			return
+		case map[string]any:
+			for key, child := range v {
+				walk(append(append([]string{}, fieldPath...), key), child)
+			}
+			return
+		case []any:
+			if len(v) == 0 {
+				return
+			}
+		}
+		if len(fieldPath) > 0 && !r.consumes(fieldPath) {
+			warnings = append(warnings, Warning{
+				Path:    strings.Join(fieldPath, "."),
+				Message: "the option is not supported by the migration and has been dropped",
+			})
+		}
+	}
+	for key, value := range grafanaObject {
+		if !ignored[key] {
+			walk([]string{key}, value)
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Path < warnings[j].Path
+	})
+	return warnings
+}
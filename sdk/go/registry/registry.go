@@ -0,0 +1,209 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry maps the kind of the plugins to the Go type of their spec, so a dashboard can be decoded back into
+// the structs of the Go SDK.
+//
+// Every plugin module provides a `Register` function at the root of the module (e.g. `github.com/perses/plugins/prometheus`)
+// recording the plugins of its Go SDK through a register function, usually the Register method of a Registry:
+//
+//	r := registry.New()
+//	err := prometheus.Register(r.Register)
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Category string
+
+const (
+	PanelCategory      Category = "Panel"
+	QueryCategory      Category = "Query"
+	DatasourceCategory Category = "Datasource"
+	VariableCategory   Category = "Variable"
+	AnnotationCategory Category = "Annotation"
+)
+
+// CategoryOf returns the category of a plugin type, as declared in the package.json of the plugin modules.
+// Every query type (TimeSeriesQuery, LogQuery, TraceQuery, ...) belongs to the query category.
+func CategoryOf(pluginType string) (Category, error) {
+	switch Category(pluginType) {
+	case PanelCategory, DatasourceCategory, VariableCategory, AnnotationCategory:
+		return Category(pluginType), nil
+	}
+	if strings.HasSuffix(pluginType, string(QueryCategory)) {
+		return QueryCategory, nil
+	}
+	return "", fmt.Errorf("unknown plugin type %q", pluginType)
+}
+
+// UnknownKindError is returned when decoding a plugin whose kind has not been registered.
+type UnknownKindError struct {
+	Kind string
+}
+
+func (e *UnknownKindError) Error() string {
+	return fmt.Sprintf("unknown plugin kind %q", e.Kind)
+}
+
+// DecodeError is returned when the spec of a registered plugin cannot be decoded into its Go type.
+type DecodeError struct {
+	Kind string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unable to decode the spec of the plugin %q: %s", e.Kind, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Entry describes a registered plugin.
+type Entry struct {
+	Kind string
+	// PluginType is the type of the plugin as declared in the package.json of the module (Panel, TimeSeriesQuery, ...).
+	PluginType string
+	Category   Category
+	// SpecType is the Go type of the plugin spec.
+	SpecType reflect.Type
+}
+
+type Registry struct {
+	mutex   sync.RWMutex
+	entries map[string]Entry
+}
+
+func New() *Registry {
+	return &Registry{
+		entries: make(map[string]Entry),
+	}
+}
+
+// Register records the Go type of the spec of a plugin.
+// spec is a value of the type to register, usually the zero value of the PluginSpec of the SDK package.
+func (r *Registry) Register(kind string, pluginType string, spec any) error {
+	if kind == "" {
+		return fmt.Errorf("plugin kind cannot be empty")
+	}
+	if spec == nil {
+		return fmt.Errorf("spec of the plugin %q cannot be nil", kind)
+	}
+	category, err := CategoryOf(pluginType)
+	if err != nil {
+		return fmt.Errorf("unable to register the plugin %q: %w", kind, err)
+	}
+	specType := reflect.TypeOf(spec)
+	if specType.Kind() == reflect.Pointer {
+		specType = specType.Elem()
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if existing, ok := r.entries[kind]; ok && existing.SpecType != specType {
+		return fmt.Errorf("plugin %q is already registered with the type %s", kind, existing.SpecType)
+	}
+	r.entries[kind] = Entry{
+		Kind:       kind,
+		PluginType: pluginType,
+		Category:   category,
+		SpecType:   specType,
+	}
+	return nil
+}
+
+// Lookup returns the entry registered for the given kind.
+func (r *Registry) Lookup(kind string) (Entry, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entry, ok := r.entries[kind]
+	return entry, ok
+}
+
+// Entries returns every registered plugin, sorted by kind.
+func (r *Registry) Entries() []Entry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entries := make([]Entry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Kind < entries[j].Kind
+	})
+	return entries
+}
+
+// Decode converts the spec of a plugin (e.g. the `Spec` field of a plugin.Plugin decoded from a dashboard)
+// into a pointer to the Go type registered for its kind.
+// The spec can be raw JSON ([]byte, json.RawMessage) or any value that can be marshalled in JSON.
+// The UnmarshalJSON method of the registered type is used, so its validation applies.
+func (r *Registry) Decode(kind string, spec any) (any, error) {
+	entry, ok := r.Lookup(kind)
+	if !ok {
+		return nil, &UnknownKindError{Kind: kind}
+	}
+	switch s := spec.(type) {
+	case json.RawMessage:
+		return decodeJSON(entry, s)
+	case []byte:
+		return decodeJSON(entry, s)
+	}
+	if spec != nil && reflect.TypeOf(spec) == reflect.PointerTo(entry.SpecType) {
+		return spec, nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, &DecodeError{Kind: kind, Err: err}
+	}
+	return decodeJSON(entry, data)
+}
+
+// DecodeJSON converts the JSON spec of a plugin into a pointer to the Go type registered for its kind.
+func (r *Registry) DecodeJSON(kind string, data []byte) (any, error) {
+	entry, ok := r.Lookup(kind)
+	if !ok {
+		return nil, &UnknownKindError{Kind: kind}
+	}
+	return decodeJSON(entry, data)
+}
+
+// DecodeYAML converts the YAML spec of a plugin into a pointer to the Go type registered for its kind.
+// The UnmarshalYAML method of the registered type is used, so its validation applies.
+func (r *Registry) DecodeYAML(kind string, data []byte) (any, error) {
+	entry, ok := r.Lookup(kind)
+	if !ok {
+		return nil, &UnknownKindError{Kind: kind}
+	}
+	result := reflect.New(entry.SpecType)
+	if err := yaml.Unmarshal(data, result.Interface()); err != nil {
+		return nil, &DecodeError{Kind: kind, Err: err}
+	}
+	return result.Interface(), nil
+}
+
+func decodeJSON(entry Entry, data []byte) (any, error) {
+	result := reflect.New(entry.SpecType)
+	if err := json.Unmarshal(data, result.Interface()); err != nil {
+		return nil, &DecodeError{Kind: entry.Kind, Err: err}
+	}
+	return result.Interface(), nil
+}
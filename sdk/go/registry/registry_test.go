@@ -0,0 +1,167 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type querySpec struct {
+	Query string `json:"query" yaml:"query"`
+	Limit int    `json:"limit,omitempty" yaml:"limit,omitempty"`
+}
+
+func (s *querySpec) validate() error {
+	if s.Query == "" {
+		return fmt.Errorf("query cannot be empty")
+	}
+	return nil
+}
+
+func (s *querySpec) UnmarshalJSON(data []byte) error {
+	type plain querySpec
+	var tmp querySpec
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *querySpec) UnmarshalYAML(unmarshal *yaml.Node) error {
+	type plain querySpec
+	var tmp querySpec
+	if err := unmarshal.Decode((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+type panelSpec struct {
+	Text string `json:"text"`
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	r := New()
+	require.NoError(t, r.Register("TestQuery", "TimeSeriesQuery", querySpec{}))
+	require.NoError(t, r.Register("TestPanel", "Panel", &panelSpec{}))
+	return r
+}
+
+func TestCategoryOf(t *testing.T) {
+	testSuite := []struct {
+		pluginType string
+		expected   Category
+	}{
+		{pluginType: "Panel", expected: PanelCategory},
+		{pluginType: "Datasource", expected: DatasourceCategory},
+		{pluginType: "Variable", expected: VariableCategory},
+		{pluginType: "Annotation", expected: AnnotationCategory},
+		{pluginType: "TimeSeriesQuery", expected: QueryCategory},
+		{pluginType: "SilencesQuery", expected: QueryCategory},
+	}
+	for _, test := range testSuite {
+		t.Run(test.pluginType, func(t *testing.T) {
+			category, err := CategoryOf(test.pluginType)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, category)
+		})
+	}
+	_, err := CategoryOf("Explore")
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	r := newTestRegistry(t)
+	entry, ok := r.Lookup("TestPanel")
+	require.True(t, ok)
+	assert.Equal(t, Entry{Kind: "TestPanel", PluginType: "Panel", Category: PanelCategory, SpecType: reflect.TypeOf(panelSpec{})}, entry)
+	assert.Len(t, r.Entries(), 2)
+
+	// registering the same type twice is allowed, another type is not.
+	assert.NoError(t, r.Register("TestPanel", "Panel", panelSpec{}))
+	assert.Error(t, r.Register("TestPanel", "Panel", querySpec{}))
+	assert.Error(t, r.Register("Other", "Explore", panelSpec{}))
+	assert.Error(t, r.Register("", "Panel", panelSpec{}))
+}
+
+func TestDecode(t *testing.T) {
+	r := newTestRegistry(t)
+	testSuite := []struct {
+		title    string
+		spec     any
+		expected any
+	}{
+		{
+			title:    "generic map",
+			spec:     map[string]any{"query": "up", "limit": float64(10)},
+			expected: &querySpec{Query: "up", Limit: 10},
+		},
+		{
+			title:    "raw JSON",
+			spec:     json.RawMessage(`{"query":"up"}`),
+			expected: &querySpec{Query: "up"},
+		},
+		{
+			title:    "already typed",
+			spec:     &querySpec{Query: "up"},
+			expected: &querySpec{Query: "up"},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := r.Decode("TestQuery", test.spec)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	_, err := r.Decode("Unknown", map[string]any{})
+	var unknownErr *UnknownKindError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "Unknown", unknownErr.Kind)
+
+	_, err = r.DecodeJSON("TestQuery", []byte(`{"query":""}`))
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "TestQuery", decodeErr.Kind)
+	assert.EqualError(t, decodeErr.Err, "query cannot be empty")
+
+	_, err = r.DecodeYAML("TestQuery", []byte("limit: 10\n"))
+	require.True(t, errors.As(err, &decodeErr))
+	assert.EqualError(t, decodeErr.Err, "query cannot be empty")
+
+	result, err := r.DecodeYAML("TestQuery", []byte("query: up\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &querySpec{Query: "up"}, result)
+}
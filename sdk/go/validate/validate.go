@@ -0,0 +1,251 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validate checks the specs emitted by the Go SDK of the plugins against their CUE schemas.
+//
+// Every plugin module exposes its CUE module through an `embed.FS` named `Schemas`, located at the root of the module
+// (e.g. `github.com/perses/plugins/prometheus`). The validator walks any value (a plugin, a panel, a whole dashboard, ...)
+// once marshalled in JSON, and checks every `{kind, spec}` object whose kind is defined by one of the given schemas.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/load"
+)
+
+const (
+	moduleFile    = "cue.mod/module.cue"
+	schemasDir    = "schemas"
+	migrateDir    = "migrate"
+	modelPackage  = "model"
+	virtualPrefix = "/perses-plugin"
+)
+
+// validationOptions are the options used to run the final validation, aligned with the ones used by the Perses server.
+var validationOptions = []cue.Option{
+	cue.Concrete(true),
+	cue.Attributes(true),
+	cue.Definitions(true),
+	cue.Hidden(true),
+}
+
+// FieldError is the error returned when a field of a plugin doesn't match its schema.
+type FieldError struct {
+	// Kind is the kind of the plugin holding the field.
+	Kind string
+	// Path is the location of the field in the validated value, e.g. `spec.panels.cpu.spec.plugin.spec.query`.
+	Path string
+	// Message describes why the field is not valid.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Kind)
+}
+
+// Validator validates values against the schemas of a set of plugins.
+type Validator struct {
+	ctx     *cue.Context
+	schemas map[string]cue.Value
+}
+
+// New loads the model schemas of every given plugin module.
+// Each file system must contain the CUE module of a plugin: `cue.mod/module.cue` and the `schemas` directory.
+// The CUE dependencies of the schemas are resolved through the CUE registry (see `CUE_REGISTRY` and `CUE_CACHE_DIR`).
+func New(pluginSchemas ...fs.FS) (*Validator, error) {
+	v := &Validator{
+		ctx:     cuecontext.New(),
+		schemas: make(map[string]cue.Value),
+	}
+	for i, fsys := range pluginSchemas {
+		if err := v.load(fmt.Sprintf("%s-%d", virtualPrefix, i), fsys); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Validate is a shortcut to validate a value against the schemas of the given plugin modules.
+func Validate(value any, pluginSchemas ...fs.FS) error {
+	v, err := New(pluginSchemas...)
+	if err != nil {
+		return err
+	}
+	return v.Validate(value)
+}
+
+// Kinds returns the sorted list of plugin kinds known by the validator.
+func (v *Validator) Kinds() []string {
+	kinds := make([]string, 0, len(v.schemas))
+	for kind := range v.schemas {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Validate marshals the given value in JSON and validates every plugin found in it.
+// Plugins whose kind is unknown to the validator are ignored.
+// The returned error joins a *FieldError for every invalid field.
+func (v *Validator) Validate(value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to marshal the value to validate: %w", err)
+	}
+	var node any
+	if unmarshalErr := json.Unmarshal(data, &node); unmarshalErr != nil {
+		return fmt.Errorf("unable to unmarshal the value to validate: %w", unmarshalErr)
+	}
+	var errs []error
+	v.walk("", node, &errs)
+	return errors.Join(errs...)
+}
+
+func (v *Validator) walk(nodePath string, node any, errs *[]error) {
+	switch n := node.(type) {
+	case map[string]any:
+		if kind, ok := n["kind"].(string); ok {
+			if spec, hasSpec := n["spec"]; hasSpec {
+				if schema, isKnown := v.schemas[kind]; isKnown {
+					*errs = append(*errs, v.validatePlugin(nodePath, kind, spec, schema)...)
+				}
+			}
+		}
+		keys := make([]string, 0, len(n))
+		for key := range n {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v.walk(joinPath(nodePath, key), n[key], errs)
+		}
+	case []any:
+		for i, item := range n {
+			v.walk(fmt.Sprintf("%s[%d]", nodePath, i), item, errs)
+		}
+	}
+}
+
+func (v *Validator) validatePlugin(nodePath string, kind string, spec any, schema cue.Value) []error {
+	// The plugin is marshalled again so that CUE makes the difference between integers and floats.
+	data, err := json.Marshal(map[string]any{"kind": kind, "spec": spec})
+	if err != nil {
+		return []error{&FieldError{Kind: kind, Path: joinPath(nodePath, "spec"), Message: err.Error()}}
+	}
+	value := v.ctx.CompileBytes(data).Unify(schema)
+	validateErr := value.Validate(validationOptions...)
+	if validateErr == nil {
+		return nil
+	}
+	var errs []error
+	for _, e := range cueerrors.Errors(validateErr) {
+		format, args := e.Msg()
+		errs = append(errs, &FieldError{
+			Kind:    kind,
+			Path:    joinPath(nodePath, strings.Join(e.Path(), ".")),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	return errs
+}
+
+// load reads the CUE module contained in fsys and builds every model schema of the plugin.
+// The files are given to CUE through an overlay mounted on root, so nothing needs to be written on disk.
+func (v *Validator) load(root string, fsys fs.FS) error {
+	if _, err := fs.Stat(fsys, moduleFile); err != nil {
+		return fmt.Errorf("invalid plugin schemas: %w", err)
+	}
+	overlay := make(map[string]load.Source)
+	modelDirs := make(map[string]bool)
+	err := fs.WalkDir(fsys, ".", func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(currentPath) != ".cue" {
+			return nil
+		}
+		data, readErr := fs.ReadFile(fsys, currentPath)
+		if readErr != nil {
+			return readErr
+		}
+		overlay[path.Join(root, currentPath)] = load.FromBytes(data)
+		if isModelFile(currentPath, data) {
+			modelDirs[path.Dir(currentPath)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read the plugin schemas: %w", err)
+	}
+	for dir := range modelDirs {
+		kind, schema, loadErr := v.loadSchema(path.Join(root, dir), overlay)
+		if loadErr != nil {
+			return loadErr
+		}
+		v.schemas[kind] = schema
+	}
+	return nil
+}
+
+func (v *Validator) loadSchema(dir string, overlay map[string]load.Source) (string, cue.Value, error) {
+	buildInstances := load.Instances([]string{}, &load.Config{Dir: dir, Package: modelPackage, Overlay: overlay})
+	if len(buildInstances) != 1 {
+		return "", cue.Value{}, fmt.Errorf("the number of build instances in %s is != 1", dir)
+	}
+	if buildInstances[0].Err != nil {
+		return "", cue.Value{}, fmt.Errorf("failed to load schema from %q: %w", dir, buildInstances[0].Err)
+	}
+	schema := v.ctx.BuildInstance(buildInstances[0])
+	if schema.Err() != nil {
+		return "", cue.Value{}, fmt.Errorf("failed to build schema from %q: %w", dir, schema.Err())
+	}
+	kind, err := schema.LookupPath(cue.ParsePath("kind")).String()
+	if err != nil {
+		return "", cue.Value{}, fmt.Errorf("invalid schema at %s: unable to read the `kind` field: %w", dir, err)
+	}
+	return kind, schema, nil
+}
+
+// isModelFile tells whether the file is part of a model schema.
+// Migration schemas are excluded as they describe how to migrate from Grafana and not the plugin itself.
+func isModelFile(filePath string, data []byte) bool {
+	if !strings.HasPrefix(filePath, schemasDir+"/") {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		if dir == migrateDir {
+			return false
+		}
+	}
+	return strings.Contains(string(data), "package "+modelPackage)
+}
+
+func joinPath(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	if child == "" {
+		return parent
+	}
+	return parent + "." + child
+}
@@ -0,0 +1,144 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchemas = fstest.MapFS{
+	"cue.mod/module.cue": {Data: []byte(`module: "github.com/perses/plugins/test@v0"
+language: version: "v0.15.1"
+`)},
+	"schemas/datasources/test.cue": {Data: []byte(`package model
+
+#kind: "TestDatasource"
+
+kind: #kind
+spec: close({
+	url?: string
+})
+
+#selector: datasource?: {kind: #kind, name?: string}
+`)},
+	"schemas/queries/test-query/query.cue": {Data: []byte(`package model
+
+import ds "github.com/perses/plugins/test/schemas/datasources:model"
+
+kind: "TestQuery"
+spec: close({
+	ds.#selector
+	query:  string
+	limit?: int
+})
+`)},
+	"schemas/queries/test-query/migrate/migrate.cue": {Data: []byte(`package migrate
+
+kind: "TestQuery"
+`)},
+}
+
+type plugin struct {
+	Kind string `json:"kind"`
+	Spec any    `json:"spec"`
+}
+
+func TestNew(t *testing.T) {
+	v, err := New(testSchemas)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestDatasource", "TestQuery"}, v.Kinds())
+
+	_, err = New(fstest.MapFS{})
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		value    any
+		expected []FieldError
+	}{
+		{
+			title: "valid plugin",
+			value: plugin{Kind: "TestQuery", Spec: map[string]any{"query": "up", "limit": 20}},
+		},
+		{
+			title: "unknown kind is ignored",
+			value: plugin{Kind: "OtherQuery", Spec: map[string]any{"foo": "bar"}},
+		},
+		{
+			title: "invalid plugin",
+			value: plugin{Kind: "TestQuery", Spec: map[string]any{"query": 1, "limit": 2.5}},
+			expected: []FieldError{
+				{Kind: "TestQuery", Path: "spec.query", Message: "conflicting values 1 and string (mismatched types int and string)"},
+				{Kind: "TestQuery", Path: "spec.limit", Message: "conflicting values 2.5 and int (mismatched types float and int)"},
+			},
+		},
+		{
+			title: "invalid plugins nested in a dashboard",
+			value: map[string]any{
+				"kind": "Dashboard",
+				"spec": map[string]any{
+					"panels": map[string]any{
+						"cpu": map[string]any{
+							"kind": "Panel",
+							"spec": map[string]any{
+								"queries": []any{
+									map[string]any{
+										"kind": "TimeSeriesQuery",
+										"spec": map[string]any{
+											"plugin": plugin{Kind: "TestQuery", Spec: map[string]any{"query": "up", "timeField": "_time"}},
+										},
+									},
+								},
+							},
+						},
+					},
+					"datasources": map[string]any{
+						"test": map[string]any{
+							"default": true,
+							"plugin":  plugin{Kind: "TestDatasource", Spec: map[string]any{"url": true}},
+						},
+					},
+				},
+			},
+			expected: []FieldError{
+				{Kind: "TestDatasource", Path: "spec.datasources.test.plugin.spec.url", Message: "conflicting values true and string (mismatched types bool and string)"},
+				{Kind: "TestQuery", Path: "spec.panels.cpu.spec.queries[0].spec.plugin.spec.timeField", Message: "field not allowed"},
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := Validate(test.value, testSchemas)
+			if len(test.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var fieldErrors []FieldError
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var fieldErr *FieldError
+				require.True(t, errors.As(e, &fieldErr))
+				fieldErrors = append(fieldErrors, *fieldErr)
+			}
+			assert.ElementsMatch(t, test.expected, fieldErrors)
+		})
+	}
+}
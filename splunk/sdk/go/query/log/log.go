@@ -22,9 +22,12 @@ import (
 const PluginKind = "SplunkLogQuery"
 
 type PluginSpec struct {
-	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
-	Query      string               `json:"query" yaml:"query"`
-	TimeField  string               `json:"timeField,omitempty" yaml:"timeField,omitempty"`
+	Datasource   *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
+	Query        string               `json:"query" yaml:"query"`
+	EarliestTime string               `json:"earliest_time,omitempty" yaml:"earliest_time,omitempty"`
+	LatestTime   string               `json:"latest_time,omitempty" yaml:"latest_time,omitempty"`
+	// Deprecated: the time field is not part of the SplunkLogQuery schema, so it is not emitted.
+	TimeField string `json:"-" yaml:"-"`
 }
 
 type Option func(plugin *Builder) error
@@ -36,7 +39,6 @@ func create(query string, options ...Option) (Builder, error) {
 
 	defaults := []Option{
 		Query(query),
-		TimeField("_time"),
 	}
 
 	for _, opt := range append(defaults, options...) {
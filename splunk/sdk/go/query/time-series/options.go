@@ -31,6 +31,22 @@ func Datasource(datasourceName string) Option {
 	}
 }
 
+func EarliestTime(earliest string) Option {
+	return func(builder *Builder) error {
+		builder.EarliestTime = earliest
+		return nil
+	}
+}
+
+func LatestTime(latest string) Option {
+	return func(builder *Builder) error {
+		builder.LatestTime = latest
+		return nil
+	}
+}
+
+// Deprecated: the time field is not part of the SplunkTimeSeriesQuery schema, the option has no effect on the emitted
+// query. Use EarliestTime and LatestTime to define the time range of the search.
 func TimeField(field string) Option {
 	return func(builder *Builder) error {
 		builder.TimeField = field
@@ -38,6 +54,8 @@ func TimeField(field string) Option {
 	}
 }
 
+// Deprecated: the value field is not part of the SplunkTimeSeriesQuery schema, the option has no effect on the emitted
+// query.
 func ValueField(field string) Option {
 	return func(builder *Builder) error {
 		builder.ValueField = field
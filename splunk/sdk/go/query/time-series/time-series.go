@@ -22,10 +22,14 @@ import (
 const PluginKind = "SplunkTimeSeriesQuery"
 
 type PluginSpec struct {
-	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
-	Query      string               `json:"query" yaml:"query"`
-	TimeField  string               `json:"timeField,omitempty" yaml:"timeField,omitempty"`
-	ValueField string               `json:"valueField,omitempty" yaml:"valueField,omitempty"`
+	Datasource   *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
+	Query        string               `json:"query" yaml:"query"`
+	EarliestTime string               `json:"earliest_time,omitempty" yaml:"earliest_time,omitempty"`
+	LatestTime   string               `json:"latest_time,omitempty" yaml:"latest_time,omitempty"`
+	// Deprecated: the time field is not part of the SplunkTimeSeriesQuery schema, so it is not emitted.
+	TimeField string `json:"-" yaml:"-"`
+	// Deprecated: the value field is not part of the SplunkTimeSeriesQuery schema, so it is not emitted.
+	ValueField string `json:"-" yaml:"-"`
 }
 
 type Option func(plugin *Builder) error
@@ -37,8 +41,6 @@ func create(query string, options ...Option) (Builder, error) {
 
 	defaults := []Option{
 		Query(query),
-		TimeField("_time"),
-		ValueField("value"),
 	}
 
 	for _, opt := range append(defaults, options...) {
@@ -0,0 +1,162 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type MappingKind string
+
+const (
+	ValueMappingKind MappingKind = "Value"
+	RangeMappingKind MappingKind = "Range"
+	RegexMappingKind MappingKind = "Regex"
+	MiscMappingKind  MappingKind = "Misc"
+)
+
+type MiscValue string
+
+const (
+	EmptyValue MiscValue = "empty"
+	NullValue  MiscValue = "null"
+	NaNValue   MiscValue = "NaN"
+	TrueValue  MiscValue = "true"
+	FalseValue MiscValue = "false"
+)
+
+// MappingResult is the text (and optionally the color) displayed in place of a matching value.
+type MappingResult struct {
+	Value string `json:"value" yaml:"value"`
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+}
+
+type ValueMappingSpec struct {
+	Value  string        `json:"value" yaml:"value"`
+	Result MappingResult `json:"result" yaml:"result"`
+}
+
+type RangeMappingSpec struct {
+	From   *float64      `json:"from,omitempty" yaml:"from,omitempty"`
+	To     *float64      `json:"to,omitempty" yaml:"to,omitempty"`
+	Result MappingResult `json:"result" yaml:"result"`
+}
+
+type RegexMappingSpec struct {
+	Pattern string        `json:"pattern" yaml:"pattern"`
+	Result  MappingResult `json:"result" yaml:"result"`
+}
+
+type MiscMappingSpec struct {
+	Value  MiscValue     `json:"value" yaml:"value"`
+	Result MappingResult `json:"result" yaml:"result"`
+}
+
+type Mapping struct {
+	Kind MappingKind `json:"kind" yaml:"kind"`
+	Spec interface{} `json:"spec" yaml:"spec"`
+}
+
+func (m *Mapping) UnmarshalJSON(data []byte) error {
+	jsonUnmarshalFunc := func(variable interface{}) error {
+		return json.Unmarshal(data, variable)
+	}
+	return m.unmarshal(jsonUnmarshalFunc, json.Marshal, json.Unmarshal)
+}
+
+func (m *Mapping) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return m.unmarshal(unmarshal, yaml.Marshal, yaml.Unmarshal)
+}
+
+func (m *Mapping) unmarshal(unmarshal func(interface{}) error, staticMarshal func(interface{}) ([]byte, error), staticUnmarshal func([]byte, interface{}) error) error {
+	var tmp Mapping
+	type plain Mapping
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	rawSpec, err := staticMarshal(tmp.Spec)
+	if err != nil {
+		return err
+	}
+	var spec interface{}
+	switch tmp.Kind {
+	case ValueMappingKind:
+		spec = &ValueMappingSpec{}
+	case RangeMappingKind:
+		spec = &RangeMappingSpec{}
+	case RegexMappingKind:
+		spec = &RegexMappingSpec{}
+	case MiscMappingKind:
+		spec = &MiscMappingSpec{}
+	default:
+		return fmt.Errorf("unknown mapping.kind %q used", tmp.Kind)
+	}
+	if unMarshalErr := staticUnmarshal(rawSpec, spec); unMarshalErr != nil {
+		return unMarshalErr
+	}
+	m.Kind = tmp.Kind
+	m.Spec = spec
+	return nil
+}
+
+// ValueMapping maps an exact value to the given result.
+func ValueMapping(value string, result MappingResult) Mapping {
+	return Mapping{
+		Kind: ValueMappingKind,
+		Spec: &ValueMappingSpec{Value: value, Result: result},
+	}
+}
+
+// RangeMapping maps the values between from and to (both included) to the given result.
+func RangeMapping(from float64, to float64, result MappingResult) Mapping {
+	return Mapping{
+		Kind: RangeMappingKind,
+		Spec: &RangeMappingSpec{From: &from, To: &to, Result: result},
+	}
+}
+
+// RangeFrom maps the values greater than or equal to the bound to the given result, without upper bound.
+func RangeFrom(from float64, result MappingResult) Mapping {
+	return Mapping{
+		Kind: RangeMappingKind,
+		Spec: &RangeMappingSpec{From: &from, Result: result},
+	}
+}
+
+// RangeTo maps the values lower than or equal to the bound to the given result, without lower bound.
+func RangeTo(to float64, result MappingResult) Mapping {
+	return Mapping{
+		Kind: RangeMappingKind,
+		Spec: &RangeMappingSpec{To: &to, Result: result},
+	}
+}
+
+// RegexMapping maps the values matching the pattern to the given result.
+func RegexMapping(pattern string, result MappingResult) Mapping {
+	return Mapping{
+		Kind: RegexMappingKind,
+		Spec: &RegexMappingSpec{Pattern: pattern, Result: result},
+	}
+}
+
+// MiscMapping maps a special value (empty, null, NaN, true, false) to the given result.
+func MiscMapping(value MiscValue, result MappingResult) Mapping {
+	return Mapping{
+		Kind: MiscMappingKind,
+		Spec: &MiscMappingSpec{Value: value, Result: result},
+	}
+}
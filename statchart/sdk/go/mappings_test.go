@@ -0,0 +1,105 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stat
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMappingsJSONRoundTrip(t *testing.T) {
+	mappings := []Mapping{
+		ValueMapping("1", MappingResult{Value: "OK", Color: "#2e7d32"}),
+		RangeMapping(0, 0.99, MappingResult{Value: "DEGRADED", Color: "#d32f2f"}),
+		RegexMapping("^5..$", MappingResult{Value: "Server error"}),
+		MiscMapping(NullValue, MappingResult{Value: "No data"}),
+	}
+
+	raw, err := json.Marshal(mappings)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `[{"kind":"Value","spec":{"value":"1","result":{"value":"OK","color":"#2e7d32"}}},{"kind":"Range","spec":{"from":0,"to":0.99,"result":{"value":"DEGRADED","color":"#d32f2f"}}},{"kind":"Regex","spec":{"pattern":"^5..$","result":{"value":"Server error"}}},{"kind":"Misc","spec":{"value":"null","result":{"value":"No data"}}}]`
+	if string(raw) != expected {
+		t.Fatalf("unexpected mappings:\n got: %s\nwant: %s", raw, expected)
+	}
+
+	var result []Mapping
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(result, mappings) {
+		t.Errorf("unexpected mappings after round trip:\n got: %+v\nwant: %+v", result, mappings)
+	}
+}
+
+func TestMappingUnknownKind(t *testing.T) {
+	var m Mapping
+	if err := json.Unmarshal([]byte(`{"kind":"Unknown","spec":{}}`), &m); err == nil {
+		t.Fatal("expected error unmarshalling a mapping with an unknown kind, got nil")
+	}
+}
+
+func TestMappingsYAMLRoundTrip(t *testing.T) {
+	mappings := []Mapping{
+		ValueMapping("down", MappingResult{Value: "Down", Color: "red"}),
+		RangeMapping(10, 20, MappingResult{Value: "Medium"}),
+		MiscMapping(NaNValue, MappingResult{Value: "Invalid"}),
+	}
+
+	raw, err := yaml.Marshal(mappings)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var result []Mapping
+	if err := yaml.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(result, mappings) {
+		t.Errorf("unexpected mappings after round trip:\n got: %+v\nwant: %+v", result, mappings)
+	}
+}
+
+func TestOpenEndedRangeMappings(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		mapping  Mapping
+		expected string
+	}{
+		{
+			title:    "range from",
+			mapping:  RangeFrom(100, MappingResult{Value: "High"}),
+			expected: `{"kind":"Range","spec":{"from":100,"result":{"value":"High"}}}`,
+		},
+		{
+			title:    "range to",
+			mapping:  RangeTo(0, MappingResult{Value: "Negative"}),
+			expected: `{"kind":"Range","spec":{"to":0,"result":{"value":"Negative"}}}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			raw, err := json.Marshal(test.mapping)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != test.expected {
+				t.Errorf("unexpected mapping:\n got: %s\nwant: %s", raw, test.expected)
+			}
+		})
+	}
+}
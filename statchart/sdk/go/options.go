@@ -13,9 +13,7 @@
 
 package stat
 
-import (
-	"github.com/perses/perses/go-sdk/common"
-)
+import "github.com/perses/perses/go-sdk/common"
 
 func Calculation(calculation common.Calculation) Option {
 	return func(builder *Builder) error {
@@ -51,3 +49,38 @@ func ValueFontSize(size int) Option {
 		return nil
 	}
 }
+
+func MetricLabel(label string) Option {
+	return func(builder *Builder) error {
+		builder.MetricLabel = label
+		return nil
+	}
+}
+
+func WithColorMode(mode ColorMode) Option {
+	return func(builder *Builder) error {
+		builder.ColorMode = mode
+		return nil
+	}
+}
+
+func WithLegendMode(mode LegendMode) Option {
+	return func(builder *Builder) error {
+		builder.LegendMode = mode
+		return nil
+	}
+}
+
+func Mappings(mappings ...Mapping) Option {
+	return func(builder *Builder) error {
+		builder.Mappings = mappings
+		return nil
+	}
+}
+
+func AddMapping(mapping Mapping) Option {
+	return func(builder *Builder) error {
+		builder.Mappings = append(builder.Mappings, mapping)
+		return nil
+	}
+}
@@ -25,12 +25,32 @@ type Sparkline struct {
 	Width float64 `json:"width,omitempty" yaml:"width,omitempty"`
 }
 
+type ColorMode string
+
+const (
+	ValueColorMode           ColorMode = "value"
+	BackgroundSolidColorMode ColorMode = "background_solid"
+	NoneColorMode            ColorMode = "none"
+)
+
+type LegendMode string
+
+const (
+	AutoLegendMode LegendMode = "auto"
+	OnLegendMode   LegendMode = "on"
+	OffLegendMode  LegendMode = "off"
+)
+
 type PluginSpec struct {
 	Calculation   common.Calculation `json:"calculation" yaml:"calculation"`
+	MetricLabel   string             `json:"metricLabel,omitempty" yaml:"metricLabel,omitempty"`
 	Format        *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
 	Thresholds    *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
 	Sparkline     *Sparkline         `json:"sparkline,omitempty" yaml:"sparkline,omitempty"`
 	ValueFontSize int                `json:"valueFontSize,omitempty" yaml:"valueFontSize,omitempty"`
+	ColorMode     ColorMode          `json:"colorMode,omitempty" yaml:"colorMode,omitempty"`
+	LegendMode    LegendMode         `json:"legendMode,omitempty" yaml:"legendMode,omitempty"`
+	Mappings      []Mapping          `json:"mappings,omitempty" yaml:"mappings,omitempty"`
 }
 
 type Option func(plugin *Builder) error
@@ -17,6 +17,8 @@ import (
 	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
 )
 
+const PluginKind = "StaticListVariable"
+
 type PluginSpec struct {
 	Values []string `json:"values" yaml:"values"`
 }
@@ -49,7 +51,7 @@ func StaticList(options ...Option) listvariable.Option {
 		if err != nil {
 			return err
 		}
-		builder.ListVariableSpec.Plugin.Kind = "StaticListVariable"
+		builder.ListVariableSpec.Plugin.Kind = PluginKind
 		builder.ListVariableSpec.Plugin.Spec = t
 		return nil
 	}
@@ -19,3 +19,24 @@ func WithLegend(legend Legend) Option {
 		return nil
 	}
 }
+
+func Mappings(mappings ...Mapping) Option {
+	return func(builder *Builder) error {
+		builder.Mappings = mappings
+		return nil
+	}
+}
+
+func AddMapping(mapping Mapping) Option {
+	return func(builder *Builder) error {
+		builder.Mappings = append(builder.Mappings, mapping)
+		return nil
+	}
+}
+
+func WithSorting(sort Sort) Option {
+	return func(builder *Builder) error {
+		builder.Sorting = sort
+		return nil
+	}
+}
@@ -13,9 +13,7 @@
 
 package statushistory
 
-import (
-	"github.com/perses/perses/go-sdk/panel"
-)
+import "github.com/perses/perses/go-sdk/panel"
 
 const PluginKind = "StatusHistoryChart"
 
@@ -46,9 +44,17 @@ type Legend struct {
 	Size     LegendSize     `json:"size,omitempty" yaml:"size,omitempty"`
 }
 
+type Sort string
+
+const (
+	AscSort  Sort = "asc"
+	DescSort Sort = "desc"
+)
+
 type PluginSpec struct {
-	Legend *Legend `json:"legend,omitempty" yaml:"legend,omitempty"`
-	// TODO missing mappings
+	Legend   *Legend   `json:"legend,omitempty" yaml:"legend,omitempty"`
+	Mappings []Mapping `json:"mappings,omitempty" yaml:"mappings,omitempty"`
+	Sorting  Sort      `json:"sorting,omitempty" yaml:"sorting,omitempty"`
 }
 
 type Option func(plugin *Builder) error
@@ -0,0 +1,109 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+// Selection enables the selection of the items (rows, series, log entries...) of a panel, used to trigger actions.
+type Selection struct {
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+type ActionType string
+
+const (
+	EventActionType   ActionType = "event"
+	WebhookActionType ActionType = "webhook"
+)
+
+type BatchMode string
+
+const (
+	IndividualBatchMode BatchMode = "individual"
+	BatchBatchMode      BatchMode = "batch"
+)
+
+type HTTPMethod string
+
+const (
+	GetMethod    HTTPMethod = "GET"
+	PostMethod   HTTPMethod = "POST"
+	PutMethod    HTTPMethod = "PUT"
+	PatchMethod  HTTPMethod = "PATCH"
+	DeleteMethod HTTPMethod = "DELETE"
+)
+
+type ContentType string
+
+const (
+	NoneContentType ContentType = "none"
+	JSONContentType ContentType = "json"
+	TextContentType ContentType = "text"
+)
+
+// ItemAction is an action that can be triggered on one or several selected items.
+// EventName is only used by event actions, while URL, Method, ContentType and Headers are only used by webhook actions.
+type ItemAction struct {
+	Type           ActionType        `json:"type" yaml:"type"`
+	Name           string            `json:"name" yaml:"name"`
+	Icon           string            `json:"icon,omitempty" yaml:"icon,omitempty"`
+	ConfirmMessage string            `json:"confirmMessage,omitempty" yaml:"confirmMessage,omitempty"`
+	Enabled        *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	BodyTemplate   string            `json:"bodyTemplate,omitempty" yaml:"bodyTemplate,omitempty"`
+	BatchMode      BatchMode         `json:"batchMode,omitempty" yaml:"batchMode,omitempty"`
+	EventName      string            `json:"eventName,omitempty" yaml:"eventName,omitempty"`
+	URL            string            `json:"url,omitempty" yaml:"url,omitempty"`
+	Method         HTTPMethod        `json:"method,omitempty" yaml:"method,omitempty"`
+	ContentType    ContentType       `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+func EventAction(name string, eventName string) ItemAction {
+	return ItemAction{
+		Type:      EventActionType,
+		Name:      name,
+		EventName: eventName,
+	}
+}
+
+func WebhookAction(name string, url string) ItemAction {
+	return ItemAction{
+		Type: WebhookActionType,
+		Name: name,
+		URL:  url,
+	}
+}
+
+type Actions struct {
+	Enabled         *bool        `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	ActionsList     []ItemAction `json:"actionsList" yaml:"actionsList"`
+	DisplayInHeader *bool        `json:"displayInHeader,omitempty" yaml:"displayInHeader,omitempty"`
+	DisplayWithItem *bool        `json:"displayWithItem,omitempty" yaml:"displayWithItem,omitempty"`
+}
+
+// NewActions returns the actions to set in a panel spec. A nil list of actions is replaced by an empty one, as the
+// schema requires the list.
+func NewActions(actions Actions) *Actions {
+	if actions.ActionsList == nil {
+		actions.ActionsList = []ItemAction{}
+	}
+	return &actions
+}
+
+// AppendAction adds the action to the actions of a panel spec, creating them when they are nil.
+func AppendAction(actions *Actions, action ItemAction) *Actions {
+	if actions == nil {
+		actions = &Actions{}
+	}
+	actions.ActionsList = append(actions.ActionsList, action)
+	return actions
+}
@@ -0,0 +1,77 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestActionsJSON(t *testing.T) {
+	enabled := true
+	webhook := WebhookAction("Silence", "https://alertmanager.example.com/api/v2/silences")
+	webhook.Method = PostMethod
+	webhook.ContentType = JSONContentType
+	webhook.BatchMode = BatchBatchMode
+	webhook.Headers = map[string]string{"Authorization": "Bearer ${token}"}
+
+	testSuites := []struct {
+		title    string
+		actions  *Actions
+		expected string
+	}{
+		{
+			title:    "empty list kept",
+			actions:  NewActions(Actions{}),
+			expected: `{"actionsList":[]}`,
+		},
+		{
+			title:    "appended to nil actions",
+			actions:  AppendAction(nil, EventAction("Select", "item-selected")),
+			expected: `{"actionsList":[{"type":"event","name":"Select","eventName":"item-selected"}]}`,
+		},
+		{
+			title:    "appended after the existing actions",
+			actions:  AppendAction(NewActions(Actions{Enabled: &enabled, DisplayInHeader: &enabled}), webhook),
+			expected: `{"enabled":true,"actionsList":[{"type":"webhook","name":"Silence","batchMode":"batch","url":"https://alertmanager.example.com/api/v2/silences","method":"POST","contentType":"json","headers":{"Authorization":"Bearer ${token}"}}],"displayInHeader":true}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			raw, err := json.Marshal(test.actions)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != test.expected {
+				t.Errorf("unexpected actions:\n got: %s\nwant: %s", raw, test.expected)
+			}
+			var result Actions
+			if err := json.Unmarshal(raw, &result); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(&result, test.actions) {
+				t.Errorf("unexpected actions after round trip:\n got: %+v\nwant: %+v", result, *test.actions)
+			}
+		})
+	}
+}
+
+func TestNewActionsKeepsTheList(t *testing.T) {
+	list := []ItemAction{EventAction("Select", "item-selected")}
+	actions := NewActions(Actions{ActionsList: list})
+	if !reflect.DeepEqual(actions.ActionsList, list) {
+		t.Errorf("unexpected actions list: %+v", actions.ActionsList)
+	}
+}
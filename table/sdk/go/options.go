@@ -14,7 +14,10 @@
 package table
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/common"
+	"github.com/perses/perses/go-sdk/panel"
 )
 
 func WithDensity(density Density) Option {
@@ -26,14 +29,28 @@ func WithDensity(density Density) Option {
 
 func WithDefaultColumWidth(width int) Option {
 	return func(builder *Builder) error {
-		builder.DefaultColumnWidth = width
+		builder.DefaultColumnWidth = FixedSize(float64(width))
+		return nil
+	}
+}
+
+func WithAutoDefaultColumnWidth() Option {
+	return func(builder *Builder) error {
+		builder.DefaultColumnWidth = AutoSize()
 		return nil
 	}
 }
 
 func WithDefaultColumHeight(height int) Option {
 	return func(builder *Builder) error {
-		builder.DefaultColumnHeight = height
+		builder.DefaultColumnHeight = FixedSize(float64(height))
+		return nil
+	}
+}
+
+func WithAutoDefaultColumnHeight() Option {
+	return func(builder *Builder) error {
+		builder.DefaultColumnHeight = AutoSize()
 		return nil
 	}
 }
@@ -73,6 +90,28 @@ func WithColumnSettings(settings []ColumnSettings) Option {
 	}
 }
 
+// ColumnPlugin renders the cells of the given column with a panel plugin, e.g. ColumnPlugin("value", stat.Chart()).
+// The column settings are created if they don't exist yet.
+func ColumnPlugin(columnName string, panelPlugin panel.Option) Option {
+	return func(builder *Builder) error {
+		plg := &panel.Builder{}
+		if err := panelPlugin(plg); err != nil {
+			return fmt.Errorf("invalid plugin for column %q: %w", columnName, err)
+		}
+		for i := range builder.ColumnSettings {
+			if builder.ColumnSettings[i].Name == columnName {
+				builder.ColumnSettings[i].Plugin = &plg.Spec.Plugin
+				return nil
+			}
+		}
+		builder.ColumnSettings = append(builder.ColumnSettings, ColumnSettings{
+			Name:   columnName,
+			Plugin: &plg.Spec.Plugin,
+		})
+		return nil
+	}
+}
+
 func WithCellSettings(settings []CellSettings) Option {
 	return func(builder *Builder) error {
 		builder.CellSettings = settings
@@ -86,3 +125,24 @@ func Transform(transforms []common.Transform) Option {
 		return nil
 	}
 }
+
+func WithSelection(selection Selection) Option {
+	return func(builder *Builder) error {
+		builder.Selection = &selection
+		return nil
+	}
+}
+
+func WithActions(actions Actions) Option {
+	return func(builder *Builder) error {
+		builder.Actions = NewActions(actions)
+		return nil
+	}
+}
+
+func AddAction(action ItemAction) Option {
+	return func(builder *Builder) error {
+		builder.Actions = AppendAction(builder.Actions, action)
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWithActions(t *testing.T) {
+	action := WebhookAction("Silence", "https://alertmanager.example.com/api/v2/silences")
+	action.Method = PostMethod
+	action.ContentType = JSONContentType
+	action.BatchMode = BatchBatchMode
+
+	builder, err := create(
+		WithSelection(Selection{Enabled: true}),
+		AddAction(action),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"selection":{"enabled":true},"actions":{"actionsList":[{"type":"webhook","name":"Silence","batchMode":"batch","url":"https://alertmanager.example.com/api/v2/silences","method":"POST","contentType":"json"}]}}`
+	if string(raw) != expected {
+		t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, expected)
+	}
+}
+
+func TestWithActionsEmptyList(t *testing.T) {
+	builder, err := create(WithActions(Actions{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"actions":{"actionsList":[]}}`
+	if string(raw) != expected {
+		t.Errorf("unexpected spec:\n got: %s\nwant: %s", raw, expected)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const autoSize = "auto"
+
+// Size is either "auto" or a fixed number of pixels.
+type Size struct {
+	Auto  bool
+	Value float64
+}
+
+// AutoSize lets the table compute the size from the content.
+func AutoSize() *Size {
+	return &Size{Auto: true}
+}
+
+// FixedSize sets the size to the given number of pixels.
+func FixedSize(value float64) *Size {
+	return &Size{Value: value}
+}
+
+func (s Size) MarshalJSON() ([]byte, error) {
+	if s.Auto {
+		return json.Marshal(autoSize)
+	}
+	return json.Marshal(s.Value)
+}
+
+func (s Size) MarshalYAML() (interface{}, error) {
+	if s.Auto {
+		return autoSize, nil
+	}
+	return s.Value, nil
+}
+
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var tmp interface{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	return s.unmarshal(tmp)
+}
+
+func (s *Size) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var tmp interface{}
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	return s.unmarshal(tmp)
+}
+
+func (s *Size) unmarshal(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if v != autoSize {
+			return fmt.Errorf("invalid size %q, only %q or a number is accepted", v, autoSize)
+		}
+		*s = Size{Auto: true}
+	case float64:
+		*s = Size{Value: v}
+	case int:
+		*s = Size{Value: float64(v)}
+	default:
+		return fmt.Errorf("invalid size %v, only %q or a number is accepted", value, autoSize)
+	}
+	return nil
+}
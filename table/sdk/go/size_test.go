@@ -0,0 +1,87 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSizeJSON(t *testing.T) {
+	testSuites := []struct {
+		title string
+		size  *Size
+		raw   string
+	}{
+		{
+			title: "auto",
+			size:  AutoSize(),
+			raw:   `"auto"`,
+		},
+		{
+			title: "fixed",
+			size:  FixedSize(120.5),
+			raw:   `120.5`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			raw, err := json.Marshal(test.size)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != test.raw {
+				t.Errorf("unexpected JSON: got %s, want %s", raw, test.raw)
+			}
+			var result Size
+			if err := json.Unmarshal(raw, &result); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if result != *test.size {
+				t.Errorf("unexpected size: got %+v, want %+v", result, *test.size)
+			}
+		})
+	}
+}
+
+func TestSizeYAML(t *testing.T) {
+	var spec PluginSpec
+	if err := yaml.Unmarshal([]byte("defaultColumnWidth: auto\ndefaultColumnHeight: 40\n"), &spec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if spec.DefaultColumnWidth == nil || !spec.DefaultColumnWidth.Auto {
+		t.Errorf("expected auto default column width, got %+v", spec.DefaultColumnWidth)
+	}
+	if spec.DefaultColumnHeight == nil || spec.DefaultColumnHeight.Value != 40 {
+		t.Errorf("expected default column height of 40, got %+v", spec.DefaultColumnHeight)
+	}
+
+	raw, err := yaml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := "defaultColumnWidth: auto\ndefaultColumnHeight: 40\n"
+	if string(raw) != expected {
+		t.Errorf("unexpected YAML:\n got: %s\nwant: %s", raw, expected)
+	}
+}
+
+func TestSizeRejectsInvalidString(t *testing.T) {
+	var s Size
+	if err := json.Unmarshal([]byte(`"large"`), &s); err == nil {
+		t.Fatal("expected error unmarshalling an invalid size, got nil")
+	}
+}
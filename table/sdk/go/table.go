@@ -19,6 +19,7 @@ import (
 
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/spec/go/plugin"
 	"gopkg.in/yaml.v3"
 )
 
@@ -58,11 +59,12 @@ type ColumnSettings struct {
 	Header            string         `json:"header,omitempty" yaml:"header,omitempty"`
 	HeaderDescription string         `json:"headerDescription,omitempty" yaml:"headerDescription,omitempty"`
 	CellDescription   string         `json:"cellDescription,omitempty" yaml:"cellDescription,omitempty"`
+	Plugin            *plugin.Plugin `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Format            *common.Format `json:"format,omitempty" yaml:"format,omitempty"`
 	Align             Align          `json:"align,omitempty" yaml:"align,omitempty"`
 	EnableSorting     bool           `json:"enableSorting,omitempty" yaml:"enableSorting,omitempty"`
 	Sort              Sort           `json:"sort,omitempty" yaml:"sort,omitempty"`
-	Width             float64        `json:"width,omitempty" yaml:"width,omitempty"`
+	Width             *Size          `json:"width,omitempty" yaml:"width,omitempty"`
 	Hide              bool           `json:"hide,omitempty" yaml:"hide,omitempty"`
 	CellSettings      []CellSettings `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
 	DataLink          *DataLink      `json:"dataLink,omitempty" yaml:"dataLink,omitempty"`
@@ -162,8 +164,8 @@ type CellSettings struct {
 
 type PluginSpec struct {
 	Density             Density            `json:"density,omitempty" yaml:"density,omitempty"`
-	DefaultColumnWidth  int                `json:"defaultColumnWidth,omitempty" yaml:"defaultColumnWidth,omitempty"`
-	DefaultColumnHeight int                `json:"defaultColumnHeight,omitempty" yaml:"defaultColumnHeight,omitempty"`
+	DefaultColumnWidth  *Size              `json:"defaultColumnWidth,omitempty" yaml:"defaultColumnWidth,omitempty"`
+	DefaultColumnHeight *Size              `json:"defaultColumnHeight,omitempty" yaml:"defaultColumnHeight,omitempty"`
 	DefaultColumnHidden bool               `json:"defaultColumnHidden,omitempty" yaml:"defaultColumnHidden,omitempty"`
 	Pagination          bool               `json:"pagination,omitempty" yaml:"pagination,omitempty"`
 	EnableFiltering     bool               `json:"enableFiltering,omitempty" yaml:"enableFiltering,omitempty"`
@@ -171,6 +173,8 @@ type PluginSpec struct {
 	ColumnSettings      []ColumnSettings   `json:"columnSettings,omitempty" yaml:"columnSettings,omitempty"`
 	CellSettings        []CellSettings     `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
 	Transforms          []common.Transform `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+	Selection           *Selection         `json:"selection,omitempty" yaml:"selection,omitempty"`
+	Actions             *Actions           `json:"actions,omitempty" yaml:"actions,omitempty"`
 }
 
 type Option func(plugin *Builder) error
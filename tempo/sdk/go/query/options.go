@@ -13,7 +13,10 @@
 
 package query
 
-import "github.com/perses/plugins/tempo/sdk/go/datasource"
+import (
+	"github.com/perses/plugins/tempo/sdk/go/datasource"
+	"github.com/perses/plugins/tempo/sdk/go/query/traceql"
+)
 
 func Expr(expr string) Option {
 	return func(builder *Builder) error {
@@ -22,6 +25,11 @@ func Expr(expr string) Option {
 	}
 }
 
+// Expression defines the query from an expression built with the traceql package.
+func Expression(expr traceql.Expr) Option {
+	return Expr(expr.String())
+}
+
 func Datasource(datasourceName string) Option {
 	return func(builder *Builder) error {
 		builder.Datasource = datasource.Selector(datasourceName)
@@ -35,3 +43,12 @@ func Limit(limit int) Option {
 		return nil
 	}
 }
+
+// Validate enables the validation of the expression with the TraceQL grammar when the query is built.
+// The references to the variables are accepted, see traceql.Validate.
+func Validate() Option {
+	return func(builder *Builder) error {
+		builder.validate = true
+		return nil
+	}
+}
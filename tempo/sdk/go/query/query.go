@@ -16,6 +16,7 @@ package query
 import (
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/go-sdk/query"
+	"github.com/perses/plugins/tempo/sdk/go/query/traceql"
 	"github.com/perses/spec/go/plugin"
 )
 
@@ -44,11 +45,18 @@ func create(query string, options ...Option) (Builder, error) {
 		}
 	}
 
+	if builder.validate {
+		if err := traceql.Validate(builder.Query); err != nil {
+			return *builder, err
+		}
+	}
+
 	return *builder, nil
 }
 
 type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
+	validate   bool
 }
 
 func TraceQL(expr string, options ...Option) query.Option {
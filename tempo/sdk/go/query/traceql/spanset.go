@@ -0,0 +1,213 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceql
+
+import "strings"
+
+// SpansetExpr is an expression selecting spansets: a spanset filter, an operation on spansets or a pipeline.
+type SpansetExpr interface {
+	Expr
+	spansetExpr()
+}
+
+// SpansetFilter selects the spans matching all the conditions, e.g. `{ resource.service.name = "api" }`.
+type SpansetFilter struct {
+	condition FieldExpr
+}
+
+// Spanset selects the spans matching all the conditions, or all the spans when no condition is given (`{}`).
+func Spanset(conditions ...FieldExpr) SpansetFilter {
+	if len(conditions) == 0 {
+		return SpansetFilter{}
+	}
+	return SpansetFilter{condition: And(conditions...)}
+}
+
+func (s SpansetFilter) String() string {
+	if s.condition == nil {
+		return "{}"
+	}
+	return "{ " + s.condition.String() + " }"
+}
+
+func (s SpansetFilter) spansetExpr() {}
+
+// SpansetOperation combines two spansets, e.g. `{ kind = server } >> { status = error }`.
+type SpansetOperation struct {
+	operator string
+	lhs      SpansetExpr
+	rhs      SpansetExpr
+}
+
+func spansetOperation(operator string, lhs SpansetExpr, rhs SpansetExpr) SpansetOperation {
+	return SpansetOperation{operator: operator, lhs: lhs, rhs: rhs}
+}
+
+// SpansetAnd selects the traces having spans matching both spansets, e.g. `{ ... } && { ... }`.
+func SpansetAnd(lhs SpansetExpr, rhs SpansetExpr) SpansetOperation {
+	return spansetOperation("&&", lhs, rhs)
+}
+
+// SpansetOr selects the spans matching any of the spansets, e.g. `{ ... } || { ... }`.
+func SpansetOr(lhs SpansetExpr, rhs SpansetExpr) SpansetOperation {
+	return spansetOperation("||", lhs, rhs)
+}
+
+// Descendant selects the spans of the descendant spanset having an ancestor in the ancestor spanset, e.g.
+// `{ resource.service.name = "frontend" } >> { status = error }`.
+func Descendant(ancestor SpansetExpr, descendant SpansetExpr) SpansetOperation {
+	return spansetOperation(">>", ancestor, descendant)
+}
+
+// Child selects the spans of the child spanset having their parent in the parent spanset (`>`).
+func Child(parent SpansetExpr, child SpansetExpr) SpansetOperation {
+	return spansetOperation(">", parent, child)
+}
+
+// Ancestor selects the spans of the ancestor spanset having a descendant in the descendant spanset (`<<`).
+func Ancestor(descendant SpansetExpr, ancestor SpansetExpr) SpansetOperation {
+	return spansetOperation("<<", descendant, ancestor)
+}
+
+// Parent selects the spans of the parent spanset having a child in the child spanset (`<`).
+func Parent(child SpansetExpr, parent SpansetExpr) SpansetOperation {
+	return spansetOperation("<", child, parent)
+}
+
+// Sibling selects the spans of the rhs spanset having a sibling in the lhs spanset (`~`).
+func Sibling(lhs SpansetExpr, rhs SpansetExpr) SpansetOperation {
+	return spansetOperation("~", lhs, rhs)
+}
+
+// NotDescendant selects the spans of the descendant spanset not having any ancestor in the ancestor spanset (`!>>`).
+func NotDescendant(ancestor SpansetExpr, descendant SpansetExpr) SpansetOperation {
+	return spansetOperation("!>>", ancestor, descendant)
+}
+
+// NotChild selects the spans of the child spanset whose parent isn't in the parent spanset (`!>`).
+func NotChild(parent SpansetExpr, child SpansetExpr) SpansetOperation {
+	return spansetOperation("!>", parent, child)
+}
+
+// NotSibling selects the spans of the rhs spanset not having any sibling in the lhs spanset (`!~`).
+func NotSibling(lhs SpansetExpr, rhs SpansetExpr) SpansetOperation {
+	return spansetOperation("!~", lhs, rhs)
+}
+
+func (o SpansetOperation) String() string {
+	return wrapSpanset(o.lhs) + " " + o.operator + " " + wrapSpanset(o.rhs)
+}
+
+func (o SpansetOperation) spansetExpr() {}
+
+// wrapSpanset adds parentheses around the operands that aren't a single spanset filter, as TraceQL evaluates the
+// operations on spansets from left to right whatever the operator.
+func wrapSpanset(expr SpansetExpr) string {
+	if _, ok := expr.(SpansetFilter); ok {
+		return expr.String()
+	}
+	return "(" + expr.String() + ")"
+}
+
+// Aggregate computes a value over the spans of each spanset, to filter the spansets in a pipeline.
+type Aggregate struct {
+	function string
+	field    FieldExpr
+}
+
+// Count counts the spans, `count()`.
+func Count() Aggregate {
+	return Aggregate{function: "count"}
+}
+
+// Avg computes the average of the field, e.g. `avg(duration)`.
+func Avg(field FieldExpr) Aggregate {
+	return Aggregate{function: "avg", field: field}
+}
+
+func Min(field FieldExpr) Aggregate {
+	return Aggregate{function: "min", field: field}
+}
+
+func Max(field FieldExpr) Aggregate {
+	return Aggregate{function: "max", field: field}
+}
+
+func Sum(field FieldExpr) Aggregate {
+	return Aggregate{function: "sum", field: field}
+}
+
+func (a Aggregate) String() string {
+	if a.field == nil {
+		return a.function + "()"
+	}
+	return a.function + "(" + a.field.String() + ")"
+}
+
+// Pipeline chains stages after a spanset expression, e.g. `{ kind = server } | by(resource.service.name) | count() > 2`.
+type Pipeline struct {
+	spanset SpansetExpr
+	stages  []string
+}
+
+// Pipe starts a pipeline from the spanset expression. The stages are added with the methods of the pipeline.
+func Pipe(spanset SpansetExpr) Pipeline {
+	if p, ok := spanset.(Pipeline); ok {
+		return p
+	}
+	return Pipeline{spanset: spanset}
+}
+
+func (p Pipeline) with(stage string) Pipeline {
+	p.stages = append(append([]string{}, p.stages...), stage)
+	return p
+}
+
+// Filter keeps the spansets whose aggregate matches the comparison, e.g. `| avg(duration) > 1s`.
+func (p Pipeline) Filter(aggregate Aggregate, operator Operator, value Static) Pipeline {
+	return p.with(aggregate.String() + " " + string(operator) + " " + value.String())
+}
+
+// Where keeps the spans matching all the conditions, e.g. `| { status = error }`.
+func (p Pipeline) Where(conditions ...FieldExpr) Pipeline {
+	return p.with(Spanset(conditions...).String())
+}
+
+// By splits the spansets by the value of the field, e.g. `| by(resource.service.name)`.
+func (p Pipeline) By(field FieldExpr) Pipeline {
+	return p.with("by(" + field.String() + ")")
+}
+
+// Coalesce merges the spansets split by By, `| coalesce()`.
+func (p Pipeline) Coalesce() Pipeline {
+	return p.with("coalesce()")
+}
+
+// Select adds the fields to the spans returned by the query, e.g. `| select(span.http.url, duration)`.
+func (p Pipeline) Select(fields ...FieldExpr) Pipeline {
+	result := make([]string, len(fields))
+	for i, f := range fields {
+		result[i] = f.String()
+	}
+	return p.with("select(" + strings.Join(result, ", ") + ")")
+}
+
+func (p Pipeline) String() string {
+	if len(p.stages) == 0 {
+		return p.spanset.String()
+	}
+	return p.spanset.String() + " | " + strings.Join(p.stages, " | ")
+}
+
+func (p Pipeline) spansetExpr() {}
@@ -0,0 +1,322 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package traceql builds and validates TraceQL expressions.
+//
+// The expressions are built from typed Go values, so the strings are always quoted and escaped:
+//
+//	traceql.Spanset(
+//		traceql.Eq(traceql.Resource("service.name"), traceql.Str("api")),
+//		traceql.Gt(traceql.SpanDuration, traceql.Dur(2*time.Second)),
+//	)
+//
+// renders `{ resource.service.name = "api" && duration > 2s }`. Validate checks an expression written by hand.
+package traceql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a TraceQL expression.
+type Expr interface {
+	// String returns the expression in the TraceQL format.
+	String() string
+}
+
+const (
+	precedenceOr = iota + 1
+	precedenceAnd
+	precedenceComparison
+	precedenceUnary
+	precedencePrimary
+)
+
+// FieldExpr is an expression evaluated on each span inside a spanset filter: an attribute, an intrinsic, a static
+// value, or a condition combining them.
+type FieldExpr interface {
+	Expr
+	precedence() int
+}
+
+// Static is a static value, e.g. a string, a number or a duration.
+type Static struct {
+	text string
+}
+
+// Str is a string value, e.g. `"api"`. The string is quoted and escaped.
+func Str(value string) Static {
+	return Static{text: strconv.Quote(value)}
+}
+
+// Int is an integer value, e.g. `200`.
+func Int(value int64) Static {
+	return Static{text: strconv.FormatInt(value, 10)}
+}
+
+// Float is a floating point value, e.g. `0.5`.
+func Float(value float64) Static {
+	return Static{text: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+// Bool is a boolean value, `true` or `false`.
+func Bool(value bool) Static {
+	return Static{text: strconv.FormatBool(value)}
+}
+
+// Dur is a duration value, e.g. `1.5s`.
+func Dur(value time.Duration) Static {
+	return Static{text: value.String()}
+}
+
+// Var is a value held by a variable, e.g. `$threshold`. The name is given without the `$` prefix.
+func Var(variable string) Static {
+	return Static{text: "$" + variable}
+}
+
+// Values of the status and kind intrinsics, and the nil value matching a missing attribute.
+var (
+	Nil = Static{text: "nil"}
+
+	StatusOk    = Static{text: "ok"}
+	StatusError = Static{text: "error"}
+	StatusUnset = Static{text: "unset"}
+
+	KindUnspecified = Static{text: "unspecified"}
+	KindInternal    = Static{text: "internal"}
+	KindServer      = Static{text: "server"}
+	KindClient      = Static{text: "client"}
+	KindProducer    = Static{text: "producer"}
+	KindConsumer    = Static{text: "consumer"}
+)
+
+func (s Static) String() string {
+	return s.text
+}
+
+func (s Static) precedence() int {
+	return precedencePrimary
+}
+
+// Intrinsic is a field of the spans defined by TraceQL itself, not by an attribute.
+type Intrinsic string
+
+const (
+	SpanDuration      Intrinsic = "duration"
+	SpanName          Intrinsic = "name"
+	SpanStatus        Intrinsic = "status"
+	SpanStatusMessage Intrinsic = "statusMessage"
+	SpanKind          Intrinsic = "kind"
+	TraceDuration     Intrinsic = "traceDuration"
+	TraceRootName     Intrinsic = "rootName"
+	TraceRootService  Intrinsic = "rootServiceName"
+)
+
+func (i Intrinsic) String() string {
+	return string(i)
+}
+
+func (i Intrinsic) precedence() int {
+	return precedencePrimary
+}
+
+// Attribute is an attribute of the spans, in a given scope.
+type Attribute struct {
+	scope string
+	name  string
+}
+
+// Attr matches the attribute in any scope, e.g. `.http.method`.
+func Attr(name string) Attribute {
+	return Attribute{name: name}
+}
+
+// Span matches the attribute of the span, e.g. `span.http.method`.
+func Span(name string) Attribute {
+	return Attribute{scope: "span", name: name}
+}
+
+// Resource matches the attribute of the resource emitting the span, e.g. `resource.service.name`.
+func Resource(name string) Attribute {
+	return Attribute{scope: "resource", name: name}
+}
+
+// Event matches the attribute of the events of the span, e.g. `event.exception.message`.
+func Event(name string) Attribute {
+	return Attribute{scope: "event", name: name}
+}
+
+// Link matches the attribute of the links of the span, e.g. `link.opentracing.ref_type`.
+func Link(name string) Attribute {
+	return Attribute{scope: "link", name: name}
+}
+
+func (a Attribute) String() string {
+	name := a.name
+	if !isAttributeName(name) {
+		name = strconv.Quote(name)
+	}
+	return a.scope + "." + name
+}
+
+func (a Attribute) precedence() int {
+	return precedencePrimary
+}
+
+// isAttributeName tells whether the attribute name can be written without quotes.
+func isAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !isAttributeRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAttributeRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_.-/", r)
+}
+
+// Operator compares two fields of a span.
+type Operator string
+
+const (
+	Equal          Operator = "="
+	NotEqual       Operator = "!="
+	GreaterThan    Operator = ">"
+	GreaterOrEqual Operator = ">="
+	LessThan       Operator = "<"
+	LessOrEqual    Operator = "<="
+	MatchRegex     Operator = "=~"
+	NotMatchRegex  Operator = "!~"
+)
+
+// Comparison compares two fields of a span, e.g. `span.http.status_code >= 500`.
+type Comparison struct {
+	lhs      FieldExpr
+	operator Operator
+	rhs      FieldExpr
+}
+
+// Compare compares the two fields with the operator.
+func Compare(lhs FieldExpr, operator Operator, rhs FieldExpr) Comparison {
+	return Comparison{lhs: lhs, operator: operator, rhs: rhs}
+}
+
+func Eq(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, Equal, rhs)
+}
+
+func Neq(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, NotEqual, rhs)
+}
+
+func Gt(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, GreaterThan, rhs)
+}
+
+func Ge(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, GreaterOrEqual, rhs)
+}
+
+func Lt(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, LessThan, rhs)
+}
+
+func Le(lhs FieldExpr, rhs FieldExpr) Comparison {
+	return Compare(lhs, LessOrEqual, rhs)
+}
+
+// Re matches the field with the regular expression, e.g. `span.http.url =~ "/api/.*"`.
+func Re(field FieldExpr, regexp string) Comparison {
+	return Compare(field, MatchRegex, Str(regexp))
+}
+
+// NotRe matches the field not matching the regular expression, e.g. `name !~ "health.*"`.
+func NotRe(field FieldExpr, regexp string) Comparison {
+	return Compare(field, NotMatchRegex, Str(regexp))
+}
+
+func (c Comparison) String() string {
+	return wrap(c.lhs, precedenceComparison+1) + " " + string(c.operator) + " " + wrap(c.rhs, precedenceComparison+1)
+}
+
+func (c Comparison) precedence() int {
+	return precedenceComparison
+}
+
+// Logical combines conditions with `&&` or `||`.
+type Logical struct {
+	operator string
+	level    int
+	exprs    []FieldExpr
+}
+
+// And matches the spans matching all the conditions, e.g. `span.http.method = "GET" && status = error`.
+func And(exprs ...FieldExpr) FieldExpr {
+	return logical("&&", precedenceAnd, exprs)
+}
+
+// Or matches the spans matching at least one of the conditions, e.g. `kind = server || kind = consumer`.
+func Or(exprs ...FieldExpr) FieldExpr {
+	return logical("||", precedenceOr, exprs)
+}
+
+func logical(operator string, level int, exprs []FieldExpr) FieldExpr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return Logical{operator: operator, level: level, exprs: exprs}
+}
+
+func (l Logical) String() string {
+	result := make([]string, len(l.exprs))
+	for i, e := range l.exprs {
+		result[i] = wrap(e, l.level)
+	}
+	return strings.Join(result, " "+l.operator+" ")
+}
+
+func (l Logical) precedence() int {
+	return l.level
+}
+
+// Negation negates a condition, see Not.
+type Negation struct {
+	expr FieldExpr
+}
+
+// Not matches the spans not matching the condition, e.g. `!(span.http.method = "GET")`.
+func Not(expr FieldExpr) Negation {
+	return Negation{expr: expr}
+}
+
+func (n Negation) String() string {
+	return "!" + wrap(n.expr, precedenceUnary)
+}
+
+func (n Negation) precedence() int {
+	return precedenceUnary
+}
+
+// wrap adds parentheses around the expression when its precedence is lower than the given one.
+func wrap(expr FieldExpr, precedence int) string {
+	if expr.precedence() < precedence {
+		return "(" + expr.String() + ")"
+	}
+	return expr.String()
+}
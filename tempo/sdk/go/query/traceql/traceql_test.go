@@ -0,0 +1,105 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExprString(t *testing.T) {
+	api := Spanset(Eq(Resource("service.name"), Str("api")))
+	testSuites := []struct {
+		title    string
+		expr     Expr
+		expected string
+	}{
+		{
+			title:    "empty spanset",
+			expr:     Spanset(),
+			expected: `{}`,
+		},
+		{
+			title:    "slow spans of a service",
+			expr:     Spanset(Eq(Resource("service.name"), Str("api")), Gt(SpanDuration, Dur(1500*time.Millisecond))),
+			expected: `{ resource.service.name = "api" && duration > 1.5s }`,
+		},
+		{
+			title:    "escaped string",
+			expr:     Spanset(Eq(Span("db.statement"), Str(`SELECT "name" FROM users`))),
+			expected: `{ span.db.statement = "SELECT \"name\" FROM users" }`,
+		},
+		{
+			title:    "quoted attribute name",
+			expr:     Spanset(Eq(Attr("my attribute"), Int(1))),
+			expected: `{ ."my attribute" = 1 }`,
+		},
+		{
+			title:    "intrinsics and keywords",
+			expr:     Spanset(Eq(SpanStatus, StatusError), Neq(SpanKind, KindInternal), Neq(Span("http.url"), Nil)),
+			expected: `{ status = error && kind != internal && span.http.url != nil }`,
+		},
+		{
+			title:    "or inside and",
+			expr:     Spanset(Or(Eq(SpanKind, KindServer), Eq(SpanKind, KindConsumer)), Re(SpanName, "GET /api/.*")),
+			expected: `{ (kind = server || kind = consumer) && name =~ "GET /api/.*" }`,
+		},
+		{
+			title:    "negation",
+			expr:     Spanset(Not(Eq(Span("http.method"), Str("GET"))), NotRe(Event("exception.message"), "timeout")),
+			expected: `{ !(span.http.method = "GET") && event.exception.message !~ "timeout" }`,
+		},
+		{
+			title:    "variable",
+			expr:     Spanset(Gt(Span("http.status_code"), Var("status")), Ge(Float(0.5), Link("weight"))),
+			expected: `{ span.http.status_code > $status && 0.5 >= link.weight }`,
+		},
+		{
+			title:    "descendant",
+			expr:     Descendant(api, Spanset(Eq(SpanStatus, StatusError))),
+			expected: `{ resource.service.name = "api" } >> { status = error }`,
+		},
+		{
+			title:    "nested structural operators",
+			expr:     Sibling(Child(api, Spanset()), NotDescendant(Spanset(Lt(TraceDuration, Dur(time.Second))), Spanset())),
+			expected: `({ resource.service.name = "api" } > {}) ~ ({ traceDuration < 1s } !>> {})`,
+		},
+		{
+			title:    "pipeline with aggregates",
+			expr:     Pipe(api).By(Span("http.route")).Filter(Avg(SpanDuration), GreaterThan, Dur(time.Second)).Filter(Count(), GreaterOrEqual, Int(3)),
+			expected: `{ resource.service.name = "api" } | by(span.http.route) | avg(duration) > 1s | count() >= 3`,
+		},
+		{
+			title:    "pipeline with select and coalesce",
+			expr:     Pipe(SpansetOr(api, Spanset(Le(TraceRootService, Str("web"))))).Where(Eq(SpanStatus, StatusError)).Coalesce().Select(Span("http.url"), SpanDuration),
+			expected: `{ resource.service.name = "api" } || { rootServiceName <= "web" } | { status = error } | coalesce() | select(span.http.url, duration)`,
+		},
+		{
+			title:    "pipeline as operand",
+			expr:     SpansetAnd(Pipe(api).Filter(Max(SpanDuration), GreaterThan, Dur(time.Minute)), Spanset()),
+			expected: `({ resource.service.name = "api" } | max(duration) > 1m0s) && {}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			actual := test.expr.String()
+			if actual != test.expected {
+				t.Errorf("unexpected expression:\n got: %s\nwant: %s", actual, test.expected)
+			}
+			if err := Validate(actual); err != nil {
+				t.Errorf("the built expression is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,878 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Error reports an invalid TraceQL expression.
+type Error struct {
+	// Line and Column locate the error in the expression, starting at 1.
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid TraceQL expression at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Validate parses the expression with the TraceQL grammar of Tempo v2.8: spanset filters, operations on spansets,
+// pipelines with aggregates, by/select/coalesce, the metrics functions followed by topk/bottomk, and the query hints
+// (`with (...)`). The scalar filters used as a whole query (e.g. `count() > 1`) are not supported.
+// On top of the syntax, it checks that the regular expressions compile and that the types of the intrinsics and of
+// the static values compared together are compatible. The references to the variables (e.g. `$service`) are
+// accepted wherever a static value is. The returned error is an *Error.
+func Validate(expr string) error {
+	tokens, err := lex(expr)
+	if err != nil {
+		return err
+	}
+	p := &parser{expr: expr, tokens: tokens}
+	if err := p.parsePipeline(true); err != nil {
+		return err
+	}
+	for p.is("with") {
+		if err := p.parseHints(); err != nil {
+			return err
+		}
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return p.errorf(tok, "unexpected %s", tok)
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenAttribute
+	tokenString
+	tokenInteger
+	tokenFloat
+	tokenDuration
+	tokenVariable
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators are sorted so that the longest operators are matched first.
+var operators = []string{
+	"!>>", "!<<", "&>>", "&<<",
+	">>", "<<", "!=", "!~", "!>", "!<", "=~", ">=", "<=", "&&", "||", "&>", "&<", "&~",
+	"{", "}", "(", ")", ",", "|", "=", ">", "<", "~", "!", "+", "-", "*", "/", "%", "^",
+}
+
+// attributeScopes are the scopes of the attributes, e.g. `span.http.method`.
+var attributeScopes = map[string]bool{
+	"span":            true,
+	"resource":        true,
+	"event":           true,
+	"link":            true,
+	"instrumentation": true,
+	"parent":          true,
+}
+
+type fieldType string
+
+const (
+	typeUnknown  fieldType = ""
+	typeBool     fieldType = "boolean"
+	typeString   fieldType = "string"
+	typeNumber   fieldType = "number"
+	typeDuration fieldType = "duration"
+	typeStatus   fieldType = "status"
+	typeKind     fieldType = "kind"
+	typeNil      fieldType = "nil"
+)
+
+// scopedIntrinsics are the intrinsics written with their scope, e.g. `span:duration`.
+var scopedIntrinsics = map[string]fieldType{
+	"span:duration":           typeDuration,
+	"span:name":               typeString,
+	"span:status":             typeStatus,
+	"span:statusMessage":      typeString,
+	"span:kind":               typeKind,
+	"span:id":                 typeString,
+	"span:parentID":           typeString,
+	"span:childCount":         typeNumber,
+	"trace:duration":          typeDuration,
+	"trace:rootName":          typeString,
+	"trace:rootService":       typeString,
+	"trace:id":                typeString,
+	"event:name":              typeString,
+	"event:timeSinceStart":    typeDuration,
+	"link:traceID":            typeString,
+	"link:spanID":             typeString,
+	"instrumentation:name":    typeString,
+	"instrumentation:version": typeString,
+}
+
+// intrinsics are the intrinsics written without their scope, e.g. `duration`.
+var intrinsics = map[string]fieldType{
+	string(SpanDuration):      typeDuration,
+	string(SpanName):          typeString,
+	string(SpanStatus):        typeStatus,
+	string(SpanStatusMessage): typeString,
+	string(SpanKind):          typeKind,
+	string(TraceDuration):     typeDuration,
+	string(TraceRootName):     typeString,
+	string(TraceRootService):  typeString,
+	"childCount":              typeNumber,
+	"nestedSetLeft":           typeNumber,
+	"nestedSetRight":          typeNumber,
+	"nestedSetParent":         typeNumber,
+}
+
+// keywords are the identifiers usable as static values.
+var keywords = map[string]fieldType{
+	"true":        typeBool,
+	"false":       typeBool,
+	"nil":         typeNil,
+	"ok":          typeStatus,
+	"error":       typeStatus,
+	"unset":       typeStatus,
+	"unspecified": typeKind,
+	"internal":    typeKind,
+	"server":      typeKind,
+	"client":      typeKind,
+	"producer":    typeKind,
+	"consumer":    typeKind,
+}
+
+var aggregates = map[string]bool{"count": true, "avg": true, "min": true, "max": true, "sum": true}
+
+// metricsArgument is the kind of the arguments of a metrics function.
+type metricsArgument int
+
+const (
+	// noArgument is used by the functions without arguments, e.g. `rate()`.
+	noArgument metricsArgument = iota
+	// attributeArgument is used by the functions taking a single attribute, e.g. `avg_over_time(duration)`.
+	attributeArgument
+	// quantilesArgument is used by quantile_over_time: an attribute followed by the quantiles.
+	quantilesArgument
+	// spansetArgument is used by compare: a spanset filter followed by up to 3 integers (topN, start and end).
+	spansetArgument
+)
+
+// metricsFunctions are the first stage of a metrics query, always at the end of the pipeline.
+var metricsFunctions = map[string]metricsArgument{
+	"rate":                noArgument,
+	"count_over_time":     noArgument,
+	"min_over_time":       attributeArgument,
+	"max_over_time":       attributeArgument,
+	"avg_over_time":       attributeArgument,
+	"sum_over_time":       attributeArgument,
+	"histogram_over_time": attributeArgument,
+	"quantile_over_time":  quantilesArgument,
+	"compare":             spansetArgument,
+}
+
+// metricsSecondStages are the functions that can follow a metrics function, e.g. `rate() by (span.foo) | topk(5)`.
+var metricsSecondStages = map[string]bool{"topk": true, "bottomk": true}
+
+var spansetOperators = map[string]bool{
+	"&&": true, "||": true, ">": true, ">>": true, "<": true, "<<": true, "~": true,
+	"!>": true, "!>>": true, "!<": true, "!<<": true, "!~": true,
+	"&>": true, "&>>": true, "&<": true, "&<<": true, "&~": true,
+}
+
+var comparisonOperators = map[string]bool{"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true, "=~": true, "!~": true}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(expr) {
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case r == '"' || r == '`':
+			end, err := scanString(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: expr[start:end], pos: start})
+			i = end
+		case r == '$':
+			end := scanVariable(expr, i)
+			if end == i {
+				return nil, newError(expr, i, "invalid variable reference")
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: expr[start:end], pos: start})
+			i = end
+		case r >= '0' && r <= '9' || r == '.' && isLeadingDotFloat(expr[i+1:]):
+			tok, err := scanNumber(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case r == '.' && i+1 < len(expr) && (expr[i+1] == '"' || isAttributeRune(rune(expr[i+1]))):
+			end, err := scanAttributeName(expr, i+1)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenAttribute, text: expr[start:end], pos: start})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(expr) && (isIdentifierByte(expr[i])) {
+				i++
+			}
+			word := expr[start:i]
+			switch {
+			case attributeScopes[word] && i+1 < len(expr) && expr[i] == '.' && (expr[i+1] == '"' || isAttributeRune(rune(expr[i+1]))):
+				end, err := scanAttributeName(expr, i+1)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenAttribute, text: expr[start:end], pos: start})
+				i = end
+			case i < len(expr) && expr[i] == ':':
+				end := i + 1
+				for end < len(expr) && isIdentifierByte(expr[end]) {
+					end++
+				}
+				intrinsic := expr[start:end]
+				if _, ok := scopedIntrinsics[intrinsic]; !ok {
+					return nil, newError(expr, start, fmt.Sprintf("unknown intrinsic %q", intrinsic))
+				}
+				tokens = append(tokens, token{kind: tokenAttribute, text: intrinsic, pos: start})
+				i = end
+			default:
+				tokens = append(tokens, token{kind: tokenIdentifier, text: word, pos: start})
+			}
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: start})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, newError(expr, i, fmt.Sprintf("unexpected character %q", r))
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// isLeadingDotFloat tells whether the text following a dot is the decimal part of a float like `.99`, and not the
+// name of an unscoped attribute.
+func isLeadingDotFloat(rest string) bool {
+	i := 0
+	for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	return i > 0 && (i == len(rest) || !isAttributeRune(rune(rest[i])))
+}
+
+func isIdentifierByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '_'
+}
+
+// scanString returns the end of the string starting at the given offset, after checking its escape sequences.
+func scanString(expr string, start int) (int, error) {
+	quote := expr[start]
+	for i := start + 1; i < len(expr); i++ {
+		switch {
+		case expr[i] == '\\' && quote == '"':
+			i++
+		case expr[i] == quote:
+			if _, err := strconv.Unquote(expr[start : i+1]); err != nil {
+				return 0, newError(expr, start, fmt.Sprintf("invalid string %s", expr[start:i+1]))
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, newError(expr, start, "unterminated string")
+}
+
+// scanVariable returns the end of the variable reference starting at the given offset: `$var` or `${var:format}`.
+func scanVariable(expr string, start int) int {
+	i := start + 1
+	if i < len(expr) && expr[i] == '{' {
+		if end := strings.IndexByte(expr[i:], '}'); end > 1 {
+			return i + end + 1
+		}
+		return start
+	}
+	for i < len(expr) && isIdentifierByte(expr[i]) {
+		i++
+	}
+	if i == start+1 {
+		return start
+	}
+	return i
+}
+
+// scanNumber reads an integer, a float or a duration like `1m30s`.
+func scanNumber(expr string, start int) (token, error) {
+	i := start
+	for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+		i++
+	}
+	kind := tokenInteger
+	if i+1 < len(expr) && expr[i] == '.' && expr[i+1] >= '0' && expr[i+1] <= '9' {
+		kind = tokenFloat
+		i++
+		for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+			i++
+		}
+	}
+	if i < len(expr) && (unicode.IsLetter(rune(expr[i])) || strings.HasPrefix(expr[i:], "µ")) {
+		for i < len(expr) && (isIdentifierByte(expr[i]) || expr[i] == '.' || strings.HasPrefix(expr[i:], "µ")) {
+			if strings.HasPrefix(expr[i:], "µ") {
+				i += len("µ")
+				continue
+			}
+			i++
+		}
+		text := expr[start:i]
+		if _, err := time.ParseDuration(text); err != nil {
+			return token{}, newError(expr, start, fmt.Sprintf("invalid duration %q", text))
+		}
+		return token{kind: tokenDuration, text: text, pos: start}, nil
+	}
+	return token{kind: kind, text: expr[start:i], pos: start}, nil
+}
+
+// scanAttributeName returns the end of the attribute name starting at the given offset, quoted or not.
+func scanAttributeName(expr string, start int) (int, error) {
+	if expr[start] == '"' {
+		return scanString(expr, start)
+	}
+	i := start
+	for i < len(expr) && isAttributeRune(rune(expr[i])) {
+		i++
+	}
+	return i, nil
+}
+
+func newError(expr string, offset int, message string) *Error {
+	line, column := position(expr, offset)
+	return &Error{Line: line, Column: column, Message: message}
+}
+
+// position converts the byte offset into a line and a column, starting at 1.
+func position(expr string, offset int) (int, int) {
+	if offset > len(expr) {
+		offset = len(expr)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if expr[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return line, offset - lineStart + 1
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+}
+
+// operand is the result of parsing a field expression.
+type operand struct {
+	typ fieldType
+	tok token
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// is tells whether the next token is the given operator or identifier.
+func (p *parser) is(text string) bool {
+	tok := p.peek()
+	return (tok.kind == tokenOperator || tok.kind == tokenIdentifier) && tok.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		tok := p.peek()
+		return p.errorf(tok, "unexpected %s, expected %q", tok, text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) *Error {
+	return newError(p.expr, tok.pos, fmt.Sprintf(format, args...))
+}
+
+// parsePipeline parses a spanset expression followed by its stages. The metrics functions are only allowed at the end
+// of the root pipeline.
+func (p *parser) parsePipeline(root bool) error {
+	if err := p.parseSpansetExpr(); err != nil {
+		return err
+	}
+	for p.is("|") {
+		p.next()
+		if tok := p.peek(); p.isMetricsFunction(tok) {
+			if !root {
+				return p.errorf(tok, "the metrics function %s must be the last stage of the query", tok)
+			}
+			return p.parseMetrics()
+		}
+		if err := p.parseStage(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseSpansetExpr() error {
+	if err := p.parseSpansetTerm(); err != nil {
+		return err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && spansetOperators[tok.text]; tok = p.peek() {
+		p.next()
+		if err := p.parseSpansetTerm(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseSpansetTerm() error {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenOperator && tok.text == "{":
+		if p.is("}") {
+			p.next()
+			return nil
+		}
+		if _, err := p.parseFieldExpr(); err != nil {
+			return err
+		}
+		return p.expect("}")
+	case tok.kind == tokenOperator && tok.text == "(":
+		if err := p.parsePipeline(false); err != nil {
+			return err
+		}
+		return p.expect(")")
+	}
+	return p.errorf(tok, "unexpected %s, expected a spanset", tok)
+}
+
+func (p *parser) parseStage() error {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator && (tok.text == "{" || tok.text == "("):
+		return p.parseSpansetExpr()
+	case tok.kind == tokenIdentifier && aggregates[tok.text]:
+		if err := p.parseAggregate(); err != nil {
+			return err
+		}
+		op := p.next()
+		if op.kind != tokenOperator || !comparisonOperators[op.text] || op.text == "=~" || op.text == "!~" {
+			return p.errorf(op, "unexpected %s, expected a comparison of the aggregate", op)
+		}
+		value := p.next()
+		switch value.kind {
+		case tokenInteger, tokenFloat, tokenDuration, tokenVariable:
+			return nil
+		case tokenIdentifier:
+			if aggregates[value.text] {
+				p.pos--
+				return p.parseAggregate()
+			}
+		}
+		return p.errorf(value, "unexpected %s, expected a number or a duration", value)
+	case tok.kind == tokenIdentifier && (tok.text == "by" || tok.text == "select"):
+		p.next()
+		return p.parseFieldList()
+	case tok.kind == tokenIdentifier && tok.text == "coalesce":
+		p.next()
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		return p.expect(")")
+	}
+	return p.errorf(tok, "unexpected %s, expected a spanset, an aggregate or a pipeline function", tok)
+}
+
+func (p *parser) isMetricsFunction(tok token) bool {
+	_, ok := metricsFunctions[tok.text]
+	return tok.kind == tokenIdentifier && ok
+}
+
+// parseMetrics parses the metrics function ending the pipeline, with its optional `by` clause, then the optional
+// second stage (topk or bottomk). No other stage can follow.
+func (p *parser) parseMetrics() error {
+	name := p.next()
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	switch metricsFunctions[name.text] {
+	case attributeArgument:
+		if err := p.parseAttribute(); err != nil {
+			return err
+		}
+	case quantilesArgument:
+		if err := p.parseAttribute(); err != nil {
+			return err
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+		for {
+			if err := p.parseNumber(); err != nil {
+				return err
+			}
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+	case spansetArgument:
+		if tok := p.peek(); !p.is("{") {
+			return p.errorf(tok, "unexpected %s, expected a spanset filter", tok)
+		}
+		if err := p.parseSpansetTerm(); err != nil {
+			return err
+		}
+		// the number of results, optionally followed by the start and the end of the time range
+		count := 0
+		for p.is(",") {
+			p.next()
+			if err := p.parseNumber(); err != nil {
+				return err
+			}
+			count++
+		}
+		if count == 2 || count > 3 {
+			return p.errorf(name, "compare takes a spanset filter followed by 0, 1 or 3 numbers, got %d", count)
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+	if p.is("by") {
+		p.next()
+		if err := p.parseAttributeList(); err != nil {
+			return err
+		}
+	}
+	if !p.is("|") {
+		return nil
+	}
+	p.next()
+	stage := p.next()
+	if stage.kind != tokenIdentifier || !metricsSecondStages[stage.text] {
+		return p.errorf(stage, "unexpected %s, only topk or bottomk can follow a metrics function", stage)
+	}
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if err := p.parseNumber(); err != nil {
+		return err
+	}
+	return p.expect(")")
+}
+
+// parseAttribute parses an attribute or an intrinsic, possibly given by a variable.
+func (p *parser) parseAttribute() error {
+	tok := p.next()
+	if _, isIntrinsic := intrinsics[tok.text]; tok.kind == tokenAttribute || tok.kind == tokenVariable || tok.kind == tokenIdentifier && isIntrinsic {
+		return nil
+	}
+	return p.errorf(tok, "unexpected %s, expected an attribute", tok)
+}
+
+// parseAttributeList parses a non-empty list of attributes between parentheses, e.g. `(span.foo, name)`.
+func (p *parser) parseAttributeList() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := p.parseAttribute(); err != nil {
+			return err
+		}
+		if !p.is(",") {
+			return p.expect(")")
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseNumber() error {
+	tok := p.next()
+	switch tok.kind {
+	case tokenInteger, tokenFloat, tokenVariable:
+		return nil
+	}
+	return p.errorf(tok, "unexpected %s, expected a number", tok)
+}
+
+// parseHints parses the hints of the query, e.g. `with (sample=true, most_recent=true)`.
+func (p *parser) parseHints() error {
+	p.next()
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		name := p.next()
+		if name.kind != tokenIdentifier {
+			return p.errorf(name, "unexpected %s, expected the name of a hint", name)
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.parsePrimary()
+		if err != nil {
+			return err
+		}
+		if _, isIntrinsic := intrinsics[value.tok.text]; value.tok.kind == tokenAttribute || isIntrinsic {
+			return p.errorf(value.tok, "unexpected %s, expected a static value", value.tok)
+		}
+		if !p.is(",") {
+			return p.expect(")")
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseAggregate() error {
+	name := p.next()
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if name.text == "count" {
+		return p.expect(")")
+	}
+	if _, err := p.parseFieldExpr(); err != nil {
+		return err
+	}
+	return p.expect(")")
+}
+
+// parseFieldList parses a non-empty list of field expressions between parentheses, e.g. `(span.foo, duration)`.
+func (p *parser) parseFieldList() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if _, err := p.parseFieldExpr(); err != nil {
+			return err
+		}
+		if !p.is(",") {
+			return p.expect(")")
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseFieldExpr() (operand, error) {
+	return p.parseBinary([][]string{{"||"}, {"&&"}}, 0)
+}
+
+// parseBinary parses the logical operators by increasing precedence, then the comparisons.
+func (p *parser) parseBinary(levels [][]string, level int) (operand, error) {
+	if level == len(levels) {
+		return p.parseComparison()
+	}
+	lhs, err := p.parseBinary(levels, level+1)
+	if err != nil {
+		return operand{}, err
+	}
+	for p.isAny(levels[level]) {
+		p.next()
+		if _, err := p.parseBinary(levels, level+1); err != nil {
+			return operand{}, err
+		}
+		lhs = operand{typ: typeBool, tok: lhs.tok}
+	}
+	return lhs, nil
+}
+
+func (p *parser) isAny(texts []string) bool {
+	for _, text := range texts {
+		if p.is(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseComparison() (operand, error) {
+	lhs, err := p.parseArithmetic(0)
+	if err != nil {
+		return operand{}, err
+	}
+	op := p.peek()
+	if op.kind != tokenOperator || !comparisonOperators[op.text] {
+		return lhs, nil
+	}
+	p.next()
+	rhs, err := p.parseArithmetic(0)
+	if err != nil {
+		return operand{}, err
+	}
+	if op.text == "=~" || op.text == "!~" {
+		if err := p.checkRegexp(rhs); err != nil {
+			return operand{}, err
+		}
+	} else if !compatible(lhs.typ, rhs.typ) {
+		return operand{}, p.errorf(op, "cannot compare a %s with a %s", lhs.typ, rhs.typ)
+	}
+	return operand{typ: typeBool, tok: lhs.tok}, nil
+}
+
+func (p *parser) checkRegexp(rhs operand) error {
+	switch {
+	case rhs.tok.kind == tokenVariable || rhs.typ == typeUnknown:
+		return nil
+	case rhs.typ != typeString:
+		return p.errorf(rhs.tok, "a regular expression must be a string, got %s", rhs.tok)
+	}
+	value, _ := strconv.Unquote(rhs.tok.text)
+	if strings.Contains(value, "$") && variableReference.MatchString(value) {
+		// the regular expression is only known once the variables are replaced
+		return nil
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		return p.errorf(rhs.tok, "invalid regular expression %s: %v", rhs.tok, err)
+	}
+	return nil
+}
+
+// variableReference matches the references to a Perses variable in a string: `$var`, `${var}` and `${var:format}`.
+var variableReference = regexp.MustCompile(`\$\w+|\$\{[^}]+\}`)
+
+func compatible(lhs fieldType, rhs fieldType) bool {
+	switch {
+	case lhs == typeUnknown || rhs == typeUnknown || lhs == typeNil || rhs == typeNil || lhs == rhs:
+		return true
+	case (lhs == typeNumber || lhs == typeDuration) && (rhs == typeNumber || rhs == typeDuration):
+		return true
+	}
+	return false
+}
+
+var arithmeticLevels = [][]string{{"+", "-"}, {"*", "/", "%"}, {"^"}}
+
+func (p *parser) parseArithmetic(level int) (operand, error) {
+	if level == len(arithmeticLevels) {
+		return p.parseUnary()
+	}
+	lhs, err := p.parseArithmetic(level + 1)
+	if err != nil {
+		return operand{}, err
+	}
+	for p.isAny(arithmeticLevels[level]) {
+		op := p.next()
+		rhs, err := p.parseArithmetic(level + 1)
+		if err != nil {
+			return operand{}, err
+		}
+		for _, o := range []operand{lhs, rhs} {
+			if o.typ != typeUnknown && o.typ != typeNumber && o.typ != typeDuration {
+				return operand{}, p.errorf(op, "the operator %q cannot be applied to a %s", op.text, o.typ)
+			}
+		}
+		if lhs.typ != rhs.typ {
+			lhs.typ = typeUnknown
+		}
+	}
+	return lhs, nil
+}
+
+func (p *parser) parseUnary() (operand, error) {
+	if p.is("!") || p.is("-") {
+		op := p.next()
+		result, err := p.parseUnary()
+		if err != nil {
+			return operand{}, err
+		}
+		result.tok = op
+		if op.text == "!" {
+			result.typ = typeBool
+		}
+		return result, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (operand, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return operand{typ: typeString, tok: tok}, nil
+	case tokenInteger, tokenFloat:
+		return operand{typ: typeNumber, tok: tok}, nil
+	case tokenDuration:
+		return operand{typ: typeDuration, tok: tok}, nil
+	case tokenVariable:
+		return operand{typ: typeUnknown, tok: tok}, nil
+	case tokenAttribute:
+		return operand{typ: scopedIntrinsics[tok.text], tok: tok}, nil
+	case tokenIdentifier:
+		if typ, ok := intrinsics[tok.text]; ok {
+			return operand{typ: typ, tok: tok}, nil
+		}
+		if typ, ok := keywords[tok.text]; ok {
+			return operand{typ: typ, tok: tok}, nil
+		}
+		return operand{}, p.errorf(tok, "unknown identifier %s, a string value must be quoted", tok)
+	case tokenOperator:
+		if tok.text == "(" {
+			result, err := p.parseFieldExpr()
+			if err != nil {
+				return operand{}, err
+			}
+			return result, p.expect(")")
+		}
+	}
+	return operand{}, p.errorf(tok, "unexpected %s, expected a value", tok)
+}
@@ -0,0 +1,198 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	testSuites := []struct {
+		title string
+		expr  string
+	}{
+		{title: "empty spanset", expr: `{}`},
+		{title: "slow spans", expr: `{ resource.service.name = "api" && duration > 2s }`},
+		{title: "raw string", expr: "{ span.http.url =~ `/api/v[0-9]+/.*` }"},
+		{title: "scoped intrinsics", expr: `{ span:status = error && trace:rootService = "web" && span:kind = server }`},
+		{title: "quoted attribute", expr: `{ span."http request" = "GET" && parent.span.foo != nil }`},
+		{title: "arithmetic", expr: `{ span.http.response_content_length / 1024 > 10 && -span.delta < 0 }`},
+		{title: "variables", expr: `{ resource.service.name = "$service" && duration > $threshold && span.http.url =~ "${path:regex}.*" }`},
+		{title: "structural operators", expr: `({ kind = server } >> { status = error }) !~ { name = "retry" } &>> {}`},
+		{title: "pipeline", expr: `{ kind = server } | by(resource.service.name) | avg(duration) > 1.5s | count() > min(span.retries) | coalesce()`},
+		{title: "nested pipeline", expr: `({ status = error } | count() > 2) || { traceDuration > 1m }`},
+		{title: "select", expr: `{ name = "checkout" } | select(span.http.url, duration, .user.id)`},
+		{title: "metrics", expr: `{ resource.service.name = "api" } | quantile_over_time(duration, .99, .5) by (span.http.route)`},
+		{title: "multiline", expr: "{\n  resource.service.name = \"api\"\n}\n| rate()"},
+		{title: "hints", expr: `{ .a = 1 } with (sample=true)`},
+		{title: "metrics with second stage and hints", expr: `{ } | rate() by (resource.service.name) | topk(10) with (sample=0.1, most_recent=true)`},
+		{title: "compare", expr: `{ resource.service.name = "api" } | compare({ status = error }, 10)`},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if err := Validate(test.expr); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateTempoExamples checks expressions from the TraceQL documentation and from the parser tests of Tempo v2.8,
+// whose grammar is implemented by Validate.
+func TestValidateTempoExamples(t *testing.T) {
+	testSuites := []struct {
+		expr  string
+		valid bool
+	}{
+		{expr: `{ .http.status_code >= 200 && .http.status_code < 300 }`, valid: true},
+		{expr: `{ span.http.method = "DELETE" && status != ok } && { span.http.method = "GET" && status = error }`, valid: true},
+		{expr: `{ resource.service.name = "frontend" } >> { resource.service.name = "database" && status = error }`, valid: true},
+		{expr: `{ .a = 1 } with (sample=true)`, valid: true},
+		{expr: `{ } | count() > 1 with (most_recent=true)`, valid: true},
+		{expr: `{ span.http.status_code = 200 } | by(resource.service.name) | count() > 2`, valid: true},
+		{expr: `{ } | select(span.http.url, resource.service.name)`, valid: true},
+		{expr: `{ resource.service.name = "api" } | rate()`, valid: true},
+		{expr: `{ } | count_over_time() by (span.http.status_code)`, valid: true},
+		{expr: `{ } | avg_over_time(span.http.response.size) by (resource.service.name)`, valid: true},
+		{expr: `{ } | quantile_over_time(duration, .5, .9, .99) by (resource.service.name)`, valid: true},
+		{expr: `{ } | histogram_over_time(duration)`, valid: true},
+		{expr: `{ } | rate() by (resource.service.name) | topk(10)`, valid: true},
+		{expr: `{ } | rate() | bottomk(5)`, valid: true},
+		{expr: `{ } | compare({ status = error })`, valid: true},
+		{expr: `{ } | compare({ status = error }, 10, 1700000000, 1700003600)`, valid: true},
+		{expr: `{ } | rate() with (sample=0.1)`, valid: true},
+		{expr: `{ } | rate() | count() > 1`},
+		{expr: `{ } | rate() | { status = error }`},
+		{expr: `{ } | topk(10)`},
+		{expr: `{ } | rate() | topk(10) | bottomk(2)`},
+		{expr: `{ } | rate(duration)`},
+		{expr: `{ } | min_over_time()`},
+		{expr: `{ } | quantile_over_time(duration)`},
+		{expr: `{ } | compare({ status = error }, 10, 20)`},
+		{expr: `{ } | compare(status = error)`},
+		{expr: `({ } | rate()) && { }`},
+		{expr: `{ } | rate() by (1)`},
+		{expr: `{ } with ()`},
+		{expr: `{ } with (sample=duration)`},
+		{expr: `{ } with (sample=true) | rate()`},
+	}
+	for _, test := range testSuites {
+		t.Run(test.expr, func(t *testing.T) {
+			err := Validate(test.expr)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		expr     string
+		expected Error
+	}{
+		{
+			title:    "empty expression",
+			expr:     ``,
+			expected: Error{Line: 1, Column: 1, Message: `unexpected end of expression, expected a spanset`},
+		},
+		{
+			title:    "unquoted string",
+			expr:     `{ resource.service.name = api }`,
+			expected: Error{Line: 1, Column: 27, Message: `unknown identifier "api", a string value must be quoted`},
+		},
+		{
+			title:    "unterminated string",
+			expr:     `{ resource.service.name = "api }`,
+			expected: Error{Line: 1, Column: 27, Message: `unterminated string`},
+		},
+		{
+			title:    "quoted status",
+			expr:     `{ status = "error" }`,
+			expected: Error{Line: 1, Column: 10, Message: `cannot compare a status with a string`},
+		},
+		{
+			title:    "quoted duration",
+			expr:     `{ duration > "2s" }`,
+			expected: Error{Line: 1, Column: 12, Message: `cannot compare a duration with a string`},
+		},
+		{
+			title:    "invalid regular expression",
+			expr:     `{ name =~ "GET (/api" }`,
+			expected: Error{Line: 1, Column: 11, Message: "invalid regular expression \"\\\"GET (/api\\\"\": error parsing regexp: missing closing ): `GET (/api`"},
+		},
+		{
+			title:    "unclosed spanset",
+			expr:     "{ kind = server }\n>> { status = error",
+			expected: Error{Line: 2, Column: 20, Message: `unexpected end of expression, expected "}"`},
+		},
+		{
+			title:    "invalid duration",
+			expr:     `{ duration > 2secs }`,
+			expected: Error{Line: 1, Column: 14, Message: `invalid duration "2secs"`},
+		},
+		{
+			title:    "unknown intrinsic",
+			expr:     `{ span:latency > 1s }`,
+			expected: Error{Line: 1, Column: 3, Message: `unknown intrinsic "span:latency"`},
+		},
+		{
+			title:    "aggregate without comparison",
+			expr:     `{} | count()`,
+			expected: Error{Line: 1, Column: 13, Message: `unexpected end of expression, expected a comparison of the aggregate`},
+		},
+		{
+			title:    "unknown pipeline stage",
+			expr:     `{} | having(count() > 1)`,
+			expected: Error{Line: 1, Column: 6, Message: `unexpected "having", expected a spanset, an aggregate or a pipeline function`},
+		},
+		{
+			title:    "stage after a metrics function",
+			expr:     `{} | rate() | count() > 1`,
+			expected: Error{Line: 1, Column: 15, Message: `unexpected "count", only topk or bottomk can follow a metrics function`},
+		},
+		{
+			title:    "metrics function in a nested pipeline",
+			expr:     `({} | rate()) && {}`,
+			expected: Error{Line: 1, Column: 7, Message: `the metrics function "rate" must be the last stage of the query`},
+		},
+		{
+			title:    "hint without value",
+			expr:     `{} with (sample)`,
+			expected: Error{Line: 1, Column: 16, Message: `unexpected ")", expected "="`},
+		},
+		{
+			title:    "trailing tokens",
+			expr:     `{ kind = server } }`,
+			expected: Error{Line: 1, Column: 19, Message: `unexpected "}"`},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			err := Validate(test.expr)
+			var actual *Error
+			if !errors.As(err, &actual) {
+				t.Fatalf("expected an *Error, got %v", err)
+			}
+			if *actual != test.expected {
+				t.Errorf("unexpected error:\n got: %+v\nwant: %+v", *actual, test.expected)
+			}
+		})
+	}
+}
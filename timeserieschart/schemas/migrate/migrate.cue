@@ -34,39 +34,41 @@ spec: {
 	// legend
 	// NB: no support of former "show" attribute from Grafana, people should migrate to latest Grafana datamodel before migrating to Perses
 	#showLegend: *#panel.options.legend.showLegend | true
-	if #panel.options.legend != _|_ if #showLegend {
+	if #panel.type == "timeseries" if #panel.options.legend != _|_ if #showLegend {
 		legend: {
-			if #panel.type == "timeseries" {
-				position: *(#panel.options.legend.placement & "right") | "bottom"
-				mode:     *(#panel.options.legend.displayMode & "table") | "list"
-
-				_calcs: *#panel.options.legend.calcs | []
-				if _calcs != [] {
-					values: [for calc in _calcs
-						if (commonMigrate.#mapping.calc[calc] != _|_) {commonMigrate.#mapping.calc[calc]},
-					]
-				}
-			}
-			if #panel.type == "graph" {
-				#rightSide: *#panel.legend.rightSide | false
-				position: [// switch
-					if #rightSide {"right"},
-					"bottom",
-				][0]
-				#alignAsTable: *#panel.legend.alignAsTable | false
-				mode: [
-					if #alignAsTable {"table"},
-					"list",
-				][0]
-				values: [for oldCalc, newCalc in commonMigrate.#mapping.calc
-					if #panel.legend[oldCalc] != _|_
-					if #panel.legend[oldCalc] == true {
-						newCalc
-					},
+			position: *(#panel.options.legend.placement & "right") | "bottom"
+			mode:     *(#panel.options.legend.displayMode & "table") | "list"
+
+			_calcs: *#panel.options.legend.calcs | []
+			if _calcs != [] {
+				values: [for calc in _calcs
+					if (commonMigrate.#mapping.calc[calc] != _|_) {commonMigrate.#mapping.calc[calc]},
 				]
 			}
 		}
 	}
+	// the legend of the former graph panel is at the root of the panel, and not in its options
+	#showGraphLegend: *#panel.legend.show | true
+	if #panel.type == "graph" if #panel.legend != _|_ if #showGraphLegend {
+		legend: {
+			#rightSide: *#panel.legend.rightSide | false
+			position: [// switch
+				if #rightSide {"right"},
+				"bottom",
+			][0]
+			#alignAsTable: *#panel.legend.alignAsTable | false
+			mode: [
+				if #alignAsTable {"table"},
+				"list",
+			][0]
+			values: [for oldCalc, newCalc in commonMigrate.#mapping.calc
+				if #panel.legend[oldCalc] != _|_
+				if #panel.legend[oldCalc] == true {
+					newCalc
+				},
+			]
+		}
+	}
 
 	// yAxis
 	#unit: *commonMigrate.#mapping.unit[#panel.fieldConfig.defaults.unit] | null
@@ -13,7 +13,11 @@
 
 package timeseries
 
-import "github.com/perses/perses/go-sdk/common"
+import (
+	"slices"
+
+	"github.com/perses/perses/go-sdk/common"
+)
 
 func WithLegend(legend Legend) Option {
 	return func(builder *Builder) error {
@@ -50,9 +54,45 @@ func WithVisual(visual Visual) Option {
 	}
 }
 
+// WithQuerySettings defines the settings of the queries. The list is copied, so the options updating the settings of a
+// query (e.g. QueryLineStyle) don't modify the list given by the caller.
 func WithQuerySettings(querySettingsList []QuerySettingsItem) Option {
 	return func(builder *Builder) error {
-		builder.QuerySettings = &querySettingsList
+		querySettings := slices.Clone(querySettingsList)
+		builder.QuerySettings = &querySettings
+		return nil
+	}
+}
+
+func WithLineStyle(style LineStyle) Option {
+	return func(builder *Builder) error {
+		if builder.Visual == nil {
+			builder.Visual = &Visual{}
+		}
+		builder.Visual.LineStyle = style
 		return nil
 	}
 }
+
+// QueryLineStyle overrides the line style of the series returned by the query at the given index.
+func QueryLineStyle(queryIndex uint, style LineStyle) Option {
+	return func(builder *Builder) error {
+		builder.querySettings(queryIndex).LineStyle = style
+		return nil
+	}
+}
+
+// querySettings returns the settings of the query at the given index, creating them if they don't exist yet.
+func (b *Builder) querySettings(queryIndex uint) *QuerySettingsItem {
+	if b.QuerySettings == nil {
+		b.QuerySettings = &[]QuerySettingsItem{}
+	}
+	settings := *b.QuerySettings
+	for i := range settings {
+		if settings[i].QueryIndex == queryIndex {
+			return &settings[i]
+		}
+	}
+	*b.QuerySettings = append(settings, QuerySettingsItem{QueryIndex: queryIndex})
+	return &(*b.QuerySettings)[len(*b.QuerySettings)-1]
+}
@@ -98,3 +98,55 @@ func TestWithVisual_WithPaletteMode(t *testing.T) {
 		t.Errorf("Expected palette mode to be %s, got %v", AutoMode, mode)
 	}
 }
+
+func TestQueryLineStyle(t *testing.T) {
+	builder := &Builder{}
+	options := []Option{
+		WithQuerySettings([]QuerySettingsItem{{QueryIndex: 0, ColorValue: "#ff0000"}}),
+		QueryLineStyle(0, DottedLineStyle),
+		QueryLineStyle(1, DashedLineStyle),
+	}
+	for _, opt := range options {
+		if err := opt(builder); err != nil {
+			t.Fatalf("option failed: %v", err)
+		}
+	}
+
+	jsonBytes, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("Failed to marshal builder: %v", err)
+	}
+
+	expected := `{"querySettings":[{"queryIndex":0,"colorValue":"#ff0000","lineStyle":"dotted"},{"queryIndex":1,"lineStyle":"dashed"}]}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestQueryLineStyleKeepsTheGivenSettings(t *testing.T) {
+	querySettings := []QuerySettingsItem{{QueryIndex: 0, ColorValue: "#ff0000"}}
+	builder := &Builder{}
+	for _, opt := range []Option{WithQuerySettings(querySettings), QueryLineStyle(0, DottedLineStyle)} {
+		if err := opt(builder); err != nil {
+			t.Fatalf("option failed: %v", err)
+		}
+	}
+
+	if querySettings[0].LineStyle != "" {
+		t.Errorf("Expected the given query settings to be unchanged, got line style %q", querySettings[0].LineStyle)
+	}
+	if (*builder.QuerySettings)[0].LineStyle != DottedLineStyle {
+		t.Errorf("Expected line style %s, got %q", DottedLineStyle, (*builder.QuerySettings)[0].LineStyle)
+	}
+}
+
+func TestWithLineStyle(t *testing.T) {
+	builder := &Builder{}
+	if err := WithLineStyle(DashedLineStyle)(builder); err != nil {
+		t.Fatalf("WithLineStyle failed: %v", err)
+	}
+
+	if builder.Visual == nil || builder.Visual.LineStyle != DashedLineStyle {
+		t.Errorf("Expected visual line style to be %s, got %+v", DashedLineStyle, builder.Visual)
+	}
+}
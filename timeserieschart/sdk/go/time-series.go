@@ -70,6 +70,14 @@ const (
 	BarDisplay  VisualDisplay = "bar"
 )
 
+type LineStyle string
+
+const (
+	SolidLineStyle  LineStyle = "solid"
+	DashedLineStyle LineStyle = "dashed"
+	DottedLineStyle LineStyle = "dotted"
+)
+
 type VisualShowPoints string
 
 const (
@@ -87,6 +95,7 @@ const (
 type Visual struct {
 	Display      VisualDisplay    `json:"display,omitempty" yaml:"display,omitempty"`
 	LineWidth    float64          `json:"lineWidth,omitempty" yaml:"lineWidth,omitempty"`
+	LineStyle    LineStyle        `json:"lineStyle,omitempty" yaml:"lineStyle,omitempty"`
 	AreaOpacity  float64          `json:"areaOpacity,omitempty" yaml:"areaOpacity,omitempty"`
 	ShowPoints   VisualShowPoints `json:"showPoints,omitempty" yaml:"showPoints,omitempty"`
 	Palette      *Palette         `json:"palette,omitempty" yaml:"palette,omitempty"`
@@ -124,7 +133,7 @@ type QuerySettingsItem struct {
 	QueryIndex  uint           `json:"queryIndex" yaml:"queryIndex"`
 	ColorMode   ColorMode      `json:"colorMode,omitempty" yaml:"colorMode,omitempty"`
 	ColorValue  string         `json:"colorValue,omitempty" yaml:"colorValue,omitempty"`
-	LineStyle   string         `json:"lineStyle,omitempty" yaml:"lineStyle,omitempty"`
+	LineStyle   LineStyle      `json:"lineStyle,omitempty" yaml:"lineStyle,omitempty"`
 	AreaOpacity float64        `json:"areaOpacity,omitempty" yaml:"areaOpacity,omitempty"`
 	Format      *common.Format `json:"format,omitempty" yaml:"format,omitempty"`
 	// NegativeY, when true, renders the query's series below the X axis (values
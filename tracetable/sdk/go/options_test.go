@@ -0,0 +1,94 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetable
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestOptionsMatchFixture builds the spec of the schema fixture with the options of the SDK.
+func TestOptionsMatchFixture(t *testing.T) {
+	builder, err := create(
+		WithPalette(AutoMode),
+		TraceLink("/datasource/${datasourceName}/trace/${traceId}"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join("..", "..", "schemas", "tests", "valid", "trace-table.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Spec PluginSpec `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("unable to decode the fixture: %v", err)
+	}
+	if !reflect.DeepEqual(builder.PluginSpec, fixture.Spec) {
+		t.Errorf("unexpected spec:\n got: %+v\nwant: %+v", builder.PluginSpec, fixture.Spec)
+	}
+}
+
+func TestVisualAndLinks(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected PluginSpec
+	}{
+		{
+			title:    "no option",
+			expected: PluginSpec{},
+		},
+		{
+			title:    "palette added to the visual",
+			options:  []Option{WithVisual(Visual{}), WithPalette(CategoricalMode)},
+			expected: PluginSpec{Visual: &Visual{Palette: &Palette{Mode: CategoricalMode}}},
+		},
+		{
+			title:    "links replaced",
+			options:  []Option{TraceLink("/trace/${traceId}"), WithLinks(Links{})},
+			expected: PluginSpec{Links: &Links{}},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(builder.PluginSpec, test.expected) {
+				t.Errorf("unexpected spec:\n got: %+v\nwant: %+v", builder.PluginSpec, test.expected)
+			}
+		})
+	}
+}
+
+func TestEmptyOptionsAreOmitted(t *testing.T) {
+	builder, err := create()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := json.Marshal(builder.PluginSpec)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(raw) != `{}` {
+		t.Errorf("unexpected spec: %s", raw)
+	}
+}
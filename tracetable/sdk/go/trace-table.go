@@ -17,9 +17,62 @@ import "github.com/perses/perses/go-sdk/panel"
 
 const PluginKind = "TraceTable"
 
-func Chart() panel.Option {
+type PaletteMode string
+
+const (
+	AutoMode        PaletteMode = "auto"
+	CategoricalMode PaletteMode = "categorical"
+)
+
+type Palette struct {
+	Mode PaletteMode `json:"mode" yaml:"mode"`
+}
+
+type Visual struct {
+	Palette *Palette `json:"palette,omitempty" yaml:"palette,omitempty"`
+}
+
+type Links struct {
+	// Trace is the link to a trace. Supported variables: datasourceName, traceId.
+	Trace string `json:"trace,omitempty" yaml:"trace,omitempty"`
+}
+
+type PluginSpec struct {
+	Visual    *Visual    `json:"visual,omitempty" yaml:"visual,omitempty"`
+	Links     *Links     `json:"links,omitempty" yaml:"links,omitempty"`
+	Selection *Selection `json:"selection,omitempty" yaml:"selection,omitempty"`
+	Actions   *Actions   `json:"actions,omitempty" yaml:"actions,omitempty"`
+}
+
+type Option func(plugin *Builder) error
+
+func create(options ...Option) (Builder, error) {
+	builder := &Builder{
+		PluginSpec: PluginSpec{},
+	}
+
+	for _, opt := range options {
+		if err := opt(builder); err != nil {
+			return *builder, err
+		}
+	}
+
+	return *builder, nil
+}
+
+type Builder struct {
+	PluginSpec `json:",inline" yaml:",inline"`
+}
+
+func Chart(options ...Option) panel.Option {
 	return func(builder *panel.Builder) error {
+		plugin, err := create(options...)
+		if err != nil {
+			return err
+		}
+
 		builder.Spec.Plugin.Kind = PluginKind
+		builder.Spec.Plugin.Spec = plugin.PluginSpec
 		return nil
 	}
 }
@@ -0,0 +1,61 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracingganttchart
+
+func WithVisual(visual Visual) Option {
+	return func(builder *Builder) error {
+		builder.Visual = &visual
+		return nil
+	}
+}
+
+func WithPalette(mode PaletteMode) Option {
+	return func(builder *Builder) error {
+		if builder.Visual == nil {
+			builder.Visual = &Visual{}
+		}
+		builder.Visual.Palette = &Palette{Mode: mode}
+		return nil
+	}
+}
+
+func WithLinks(links Links) Option {
+	return func(builder *Builder) error {
+		builder.Links = &links
+		return nil
+	}
+}
+
+func TraceLink(url string) Option {
+	return func(builder *Builder) error {
+		if builder.Links == nil {
+			builder.Links = &Links{}
+		}
+		builder.Links.Trace = url
+		return nil
+	}
+}
+
+func AttributeLink(name string, url string) Option {
+	return func(builder *Builder) error {
+		if builder.Links == nil {
+			builder.Links = &Links{}
+		}
+		builder.Links.Attributes = append(builder.Links.Attributes, CustomAttributeLink{
+			Name: name,
+			Link: url,
+		})
+		return nil
+	}
+}
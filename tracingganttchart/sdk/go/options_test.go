@@ -0,0 +1,89 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracingganttchart
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestOptionsMatchFixture builds the spec of the schema fixture with the options of the SDK.
+func TestOptionsMatchFixture(t *testing.T) {
+	builder, err := create(
+		WithVisual(Visual{Palette: &Palette{Mode: AutoMode}}),
+		TraceLink("/datasource/${datasourceName}/trace/${traceId}"),
+		AttributeLink("k8s.pod.name", "/namespace/${k8s_namespace_name}/pod/${k8s_pod_name}"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join("..", "..", "schemas", "tests", "valid", "tracing-gantt-chart.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Spec PluginSpec `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("unable to decode the fixture: %v", err)
+	}
+	if !reflect.DeepEqual(builder.PluginSpec, fixture.Spec) {
+		t.Errorf("unexpected spec:\n got: %+v\nwant: %+v", builder.PluginSpec, fixture.Spec)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected PluginSpec
+	}{
+		{
+			title:    "no link",
+			expected: PluginSpec{},
+		},
+		{
+			title:   "attribute links appended",
+			options: []Option{AttributeLink("service.name", "/service/${service_name}"), AttributeLink("db.name", "/db/${db_name}")},
+			expected: PluginSpec{Links: &Links{Attributes: []CustomAttributeLink{
+				{Name: "service.name", Link: "/service/${service_name}"},
+				{Name: "db.name", Link: "/db/${db_name}"},
+			}}},
+		},
+		{
+			title:    "links replaced",
+			options:  []Option{AttributeLink("service.name", "/service/${service_name}"), WithLinks(Links{Trace: "/trace/${traceId}"})},
+			expected: PluginSpec{Links: &Links{Trace: "/trace/${traceId}"}},
+		},
+		{
+			title:    "palette",
+			options:  []Option{WithPalette(CategoricalMode)},
+			expected: PluginSpec{Visual: &Visual{Palette: &Palette{Mode: CategoricalMode}}},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(builder.PluginSpec, test.expected) {
+				t.Errorf("unexpected spec:\n got: %+v\nwant: %+v", builder.PluginSpec, test.expected)
+			}
+		})
+	}
+}